@@ -0,0 +1,62 @@
+package cart
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// abandonedBatch limits number of Carts marked as abandoned in one transaction.
+const abandonedBatch = 100
+
+type abandonedStorage interface {
+	MarkAbandonedCarts(ctx context.Context, idleBefore, now time.Time, limit int) (int, error)
+}
+
+// AbandonmentDetector finds non-empty open Carts nobody changed for longer than the threshold and writes
+// EventCartAbandoned with the User ID and LineItems of each of them to the outbox. Relay delivers the Events
+// to its Publisher, e.g. to send reminders. The Event is written once per idle period, a change of the Cart starts a new one.
+type AbandonmentDetector struct {
+	storage   abandonedStorage
+	threshold time.Duration
+	interval  time.Duration
+}
+
+// NewAbandonmentDetector returns AbandonmentDetector that checks Carts of the Storage idle longer than the threshold every interval.
+func NewAbandonmentDetector(s *Storage, threshold, interval time.Duration) *AbandonmentDetector {
+	return &AbandonmentDetector{storage: s, threshold: threshold, interval: interval}
+}
+
+// detect marks idle Carts as abandoned in batches until there are no more of them.
+func (d *AbandonmentDetector) detect(ctx context.Context, now time.Time) error {
+	idleBefore := now.Add(-d.threshold)
+
+	for {
+		n, err := d.storage.MarkAbandonedCarts(ctx, idleBefore, now, abandonedBatch)
+		if err != nil {
+			return err
+		}
+
+		if n < abandonedBatch {
+			return nil
+		}
+	}
+}
+
+// Run detects abandoned Carts until the context is canceled.
+func (d *AbandonmentDetector) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.detect(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to detect abandoned carts: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type abandonedStorageMock struct {
+	idle       int
+	idleBefore time.Time
+	calls      int
+	err        error
+}
+
+func (m *abandonedStorageMock) MarkAbandonedCarts(ctx context.Context, idleBefore, now time.Time, limit int) (int, error) {
+	m.calls++
+	m.idleBefore = idleBefore
+
+	if m.err != nil {
+		return 0, m.err
+	}
+
+	n := limit
+	if n > m.idle {
+		n = m.idle
+	}
+	m.idle -= n
+
+	return n, nil
+}
+
+func TestAbandonmentDetectorDetect(t *testing.T) {
+	var (
+		now      = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+		storage  = &abandonedStorageMock{idle: abandonedBatch}
+		detector = &AbandonmentDetector{storage: storage, threshold: 2 * time.Hour}
+	)
+
+	if err := detector.detect(context.Background(), now); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	// The last full batch is followed by an empty one.
+	if storage.idle != 0 || storage.calls != 2 {
+		t.Errorf("Got %d idle Carts left after %d batches, expected all Carts marked in 2 batches", storage.idle, storage.calls)
+	}
+
+	if expected := now.Add(-2 * time.Hour); !storage.idleBefore.Equal(expected) {
+		t.Errorf("Got idle time: %s, expected: %s", storage.idleBefore, expected)
+	}
+}
+
+func TestAbandonmentDetectorDetectError(t *testing.T) {
+	var (
+		expected = errors.New("storage error")
+		detector = &AbandonmentDetector{storage: &abandonedStorageMock{idle: 1, err: expected}, threshold: time.Hour}
+	)
+
+	if err := detector.detect(context.Background(), time.Now()); err != expected {
+		t.Errorf("Got error: %v, expected: %v", err, expected)
+	}
+}
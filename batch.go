@@ -0,0 +1,141 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+// maxOperations in a single batch.
+const maxOperations = 100
+
+var (
+	errNoOperations      = errors.New("no operations in the batch")
+	errTooManyOperations = errors.New("too many operations in the batch")
+	errUnknownOperation  = errors.New("unknown operation")
+	errInvalidQuantity   = errors.New("quantity must be positive")
+)
+
+// OperationType defines what an Operation does to a Cart.
+type OperationType int
+
+const (
+	// OperationAdd adds Quantity units of the Product.
+	OperationAdd OperationType = iota
+	// OperationSet replaces quantity of the Product, zero Quantity removes the Product.
+	OperationSet
+	// OperationRemove removes Quantity units of the Product or the whole LineItem when Quantity is zero.
+	OperationRemove
+	// OperationEmpty removes all LineItems.
+	OperationEmpty
+)
+
+// Operation is a single change of a Cart applied as a part of a batch.
+// LineItem is identified by the Product and Options, Note is only used by OperationAdd.
+type Operation struct {
+	Type      OperationType
+	ProductID int64
+	Options   Options
+	Quantity  uint32
+	Note      string
+
+	// limit of the Product quantity according to the ProductCatalog, zero means there is no limit.
+	limit uint32
+}
+
+// OperationResult describes the outcome of an Operation. Quantity of the LineItem is the one after the Operation.
+// Err is set for the Operation that failed the batch, Operations after it are Skipped.
+type OperationResult struct {
+	Quantity uint32
+	Err      error
+	Skipped  bool
+}
+
+// validate the Operation, normalize its Options and look up quantity limit of the Product.
+func (c *Carts) validate(ctx context.Context, op *Operation) error {
+	var err error
+
+	if op.Options, err = op.Options.normalize(); err != nil {
+		return err
+	}
+	if op.Note, err = validNote(op.Note); err != nil {
+		return err
+	}
+
+	switch op.Type {
+	case OperationAdd:
+		if op.Quantity == 0 {
+			return errInvalidQuantity
+		}
+	case OperationSet:
+		if op.Quantity == 0 {
+			return nil
+		}
+	case OperationRemove, OperationEmpty:
+		return nil
+	default:
+		return errUnknownOperation
+	}
+
+	limit, err := c.orderable(ctx, op.ProductID)
+	if err != nil {
+		return err
+	}
+
+	if op.Type == OperationSet && limit != 0 && op.Quantity > limit {
+		return errQuantityTooLarge
+	}
+
+	op.limit = limit
+
+	return nil
+}
+
+// Apply Operations to a Cart in order. Either all Operations are applied or none of them.
+// Results are returned for every Operation, error is the one of the first failed Operation.
+func (c *Carts) Apply(ctx context.Context, cartID int64, ops []Operation) ([]OperationResult, error) {
+	if err := c.authorize(ctx, cartID, RoleEditor); err != nil {
+		return nil, err
+	}
+
+	if len(ops) == 0 {
+		return nil, errNoOperations
+	}
+	if len(ops) > maxOperations {
+		return nil, errTooManyOperations
+	}
+
+	ops = append([]Operation(nil), ops...)
+	results := make([]OperationResult, len(ops))
+
+	fail := func(i int, err error) ([]OperationResult, error) {
+		results[i].Err = err
+		for j := i + 1; j < len(results); j++ {
+			results[j].Skipped = true
+		}
+		return results, err
+	}
+
+	for i := range ops {
+		if err := c.validate(ctx, &ops[i]); err != nil {
+			return fail(i, err)
+		}
+	}
+
+	quantities, failed, err := c.storage.ApplyOperations(c.enforceRules(ctx), cartID, ops)
+	if err != nil {
+		log.Printf("Failed to apply operations to the Cart: %d, error: %s", cartID, err)
+		if failed < 0 {
+			return nil, err
+		}
+		return fail(failed, err)
+	}
+
+	for i, q := range quantities {
+		results[i].Quantity = q
+	}
+
+	c.changes.notify(cartID)
+
+	return results, nil
+}
@@ -0,0 +1,133 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestApply(t *testing.T) {
+	var (
+		ctx     = WithTrustedService(context.Background())
+		errDB   = errors.New("db is down")
+		catalog = StaticCatalog{
+			1: {ID: 1, Active: true},
+			2: {ID: 2, Active: true, MaxQuantity: 3},
+			3: {ID: 3},
+		}
+	)
+
+	cases := []struct {
+		name            string
+		ops             []Operation
+		failed          int
+		storageErr      error
+		expectedLimits  []uint32
+		expectedResults []OperationResult
+		expectedError   error
+	}{
+		{
+			name: "Committed",
+			ops: []Operation{
+				{Type: OperationAdd, ProductID: 1, Quantity: 2},
+				{Type: OperationAdd, ProductID: 2, Quantity: 1},
+				{Type: OperationRemove, ProductID: 5},
+			},
+			failed:          -1,
+			expectedLimits:  []uint32{0, 3, 0},
+			expectedResults: []OperationResult{{Quantity: 1}, {Quantity: 2}, {Quantity: 3}},
+		},
+		{
+			name: "Invalid operation",
+			ops: []Operation{
+				{Type: OperationAdd, ProductID: 1, Quantity: 2},
+				{Type: OperationSet, ProductID: 2, Quantity: 4},
+				{Type: OperationEmpty},
+			},
+			expectedResults: []OperationResult{{}, {Err: errQuantityTooLarge}, {Skipped: true}},
+			expectedError:   errQuantityTooLarge,
+		},
+		{
+			name: "Inactive Product",
+			ops: []Operation{
+				{Type: OperationAdd, ProductID: 3, Quantity: 1},
+			},
+			expectedResults: []OperationResult{{Err: errProductInactive}},
+			expectedError:   errProductInactive,
+		},
+		{
+			name: "Zero quantity",
+			ops: []Operation{
+				{Type: OperationAdd, ProductID: 1},
+			},
+			expectedResults: []OperationResult{{Err: errInvalidQuantity}},
+			expectedError:   errInvalidQuantity,
+		},
+		{
+			name: "Failed in storage",
+			ops: []Operation{
+				{Type: OperationAdd, ProductID: 1, Quantity: 2},
+				{Type: OperationRemove, ProductID: 5, Quantity: 1},
+				{Type: OperationSet, ProductID: 1, Quantity: 1},
+			},
+			failed:          1,
+			storageErr:      errNotEnoughQuantity,
+			expectedLimits:  []uint32{0, 0, 0},
+			expectedResults: []OperationResult{{}, {Err: errNotEnoughQuantity}, {Skipped: true}},
+			expectedError:   errNotEnoughQuantity,
+		},
+		{
+			name: "Failed before operations",
+			ops: []Operation{
+				{Type: OperationAdd, ProductID: 1, Quantity: 2},
+			},
+			failed:         -1,
+			storageErr:     errDB,
+			expectedLimits: []uint32{0},
+			expectedError:  errDB,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			storage := &StorageMock{
+				ApplyOperationsFunc: func(ctx context.Context, cartID int64, ops []Operation) ([]uint32, int, error) {
+					var limits []uint32
+					for _, op := range ops {
+						limits = append(limits, op.limit)
+					}
+					if diff := cmp.Diff(c.expectedLimits, limits); diff != "" {
+						t.Errorf("Limits mismatch (-expected +got):\n%s", diff)
+					}
+
+					if c.storageErr != nil {
+						return nil, c.failed, c.storageErr
+					}
+
+					quantities := make([]uint32, len(ops))
+					for i := range ops {
+						quantities[i] = uint32(i + 1)
+					}
+					return quantities, -1, nil
+				},
+			}
+
+			results, err := New(storage, WithCatalog(catalog)).Apply(ctx, 1, c.ops)
+			if err != c.expectedError {
+				t.Fatalf("Got error: %v, expected: %v", err, c.expectedError)
+			}
+
+			if diff := cmp.Diff(c.expectedResults, results, cmp.Comparer(func(a, b error) bool { return a == b })); diff != "" {
+				t.Errorf("Results mismatch (-expected +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyRejectsEmptyBatch(t *testing.T) {
+	if _, err := New(&StorageMock{}).Apply(WithTrustedService(context.Background()), 1, nil); err != errNoOperations {
+		t.Errorf("Got error: %v, expected: %v", err, errNoOperations)
+	}
+}
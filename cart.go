@@ -11,66 +11,269 @@ import (
 var (
 	errNotImplemented = errors.New("not implemented")
 	errNotFound       = errors.New("not found")
+
+	errLineItemNotFound  = errors.New("product not found in the cart")
+	errNotEnoughQuantity = errors.New("not enough product quantity in the cart")
+	errQuantityTooLarge  = errors.New("quantity is too large")
+	errVersionMismatch   = errors.New("cart version does not match the expected one")
 )
 
 type storage interface {
-	AddProduct(ctx context.Context, cartID, productID int64, quantity uint32) error
-	DeleteProduct(ctx context.Context, cartID, productID int64) error
+	AddProduct(ctx context.Context, cartID, productID int64, options Options, quantity uint32, note string, limit uint32) error
+	DeleteProduct(ctx context.Context, cartID, productID int64, options Options) error
+	SetProductQuantity(ctx context.Context, cartID, productID int64, options Options, quantity uint32) error
+	RemoveProductUnits(ctx context.Context, cartID, productID int64, options Options, quantity uint32) error
+	SaveForLater(ctx context.Context, cartID, productID int64, options Options) error
+	MoveToCart(ctx context.Context, cartID, productID int64, options Options, limit uint32) error
+	MergeCarts(ctx context.Context, sourceID, targetID int64, strategy MergeStrategy) (Cart, error)
 	CartByID(ctx context.Context, id int64) (Cart, error)
+	CartsByUser(ctx context.Context, userID, beforeID int64, limit int) ([]Cart, error)
+	ActiveCart(ctx context.Context, userID int64) (Cart, error)
 	CreateCart(ctx context.Context, cart Cart) (Cart, error)
+	GetOrCreateCart(ctx context.Context, cart Cart) (Cart, error)
+	CloneCart(ctx context.Context, sourceID, sourceVersion int64, cart Cart) (Cart, error)
 	DeleteCart(ctx context.Context, cartID int64) error
+	RestoreCart(ctx context.Context, cartID int64, deletedAfter time.Time) error
 	DeleteLineItems(ctx context.Context, cartID int64) error
+	ApplyOperations(ctx context.Context, cartID int64, ops []Operation) ([]uint32, int, error)
+	UpdateStatus(ctx context.Context, cartID int64, from, to Status, ts time.Time) (Cart, error)
+	ReserveIdempotencyKey(ctx context.Context, key, method string, now time.Time, ttl time.Duration) ([]byte, bool, error)
+	SaveIdempotentResponse(ctx context.Context, key, method string, resp []byte, expiresAt time.Time) error
+	ReleaseIdempotencyKey(ctx context.Context, key, method string) error
+	AddCoupon(ctx context.Context, cartID int64, code string, ts time.Time) error
+	DeleteCoupon(ctx context.Context, cartID int64, code string) error
+	Reservations(ctx context.Context, cartID int64) (map[int64]Reservation, error)
+	SaveReservations(ctx context.Context, cartID int64, reservations map[int64]Reservation) error
+	ExpiredReservations(ctx context.Context, before time.Time, limit int) ([]reservedLineItem, error)
+	History(ctx context.Context, cartID, beforeID int64, limit int) ([]HistoryEntry, error)
+	CreateSnapshot(ctx context.Context, snapshot Snapshot) (Snapshot, error)
+	Snapshot(ctx context.Context, id int64) (Snapshot, error)
+	MemberRole(ctx context.Context, cartID, userID int64) (Role, error)
+	Members(ctx context.Context, cartID int64) ([]Member, error)
+	AddMember(ctx context.Context, cartID int64, m Member) error
+	RemoveMember(ctx context.Context, cartID, userID int64) error
 }
 
 // Carts contains all business logic realated to this microservice.
 type Carts struct {
-	storage storage
+	storage   storage
+	prices    PriceProvider
+	coupons   CouponProvider
+	catalog   ProductCatalog
+	inventory Inventory
+	changes   *broadcaster
+	rules     *Rules
+	retention time.Duration
 }
 
 // New builds and returns new instance of Carts that is ready for use.
-func New(s storage) *Carts {
-	return &Carts{storage: s}
+func New(s storage, opts ...Option) *Carts {
+	c := &Carts{storage: s, changes: newBroadcaster(), retention: DefaultRetention}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
-// LineItem represents single SKU and quantity.
+// LineItem represents single SKU and quantity. Options, e.g. size and color, make separate LineItems
+// of the same Product. Note is a free-form comment of the User, e.g. a gift message.
+// UnitPrice and LineTotal are in minor units of the Cart currency.
+// ReservationID is set while stock of the Product is reserved for the Cart being checked out.
 type LineItem struct {
-	ProductID int64
-	Quantity  uint32
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ProductID     int64
+	Quantity      uint32
+	Options       Options
+	Note          string
+	UnitPrice     int64
+	LineTotal     int64
+	ReservationID string
+	ReservedUntil time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
-// Cart holds LineItems for User.
+// Cart holds LineItems for User. SavedItems are parked by the User, they are not priced and not ordered.
+// Version is incremented by every modification of the Cart.
+// Subtotal is a sum of LineItem totals in minor units of the Currency.
+// Total is the Subtotal with Discounts of the applied Coupons taken off.
 type Cart struct {
-	ID        int64
-	UserID    int64
-	Items     []LineItem
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID            int64
+	UserID        int64
+	Status        Status
+	Version       int64
+	Items         []LineItem
+	SavedItems    []LineItem
+	Coupons       []string
+	Subtotal      int64
+	Discounts     []Discount
+	DiscountTotal int64
+	Total         int64
+	Currency      string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
-// AddProduct to a Cart.
-func (c *Carts) AddProduct(ctx context.Context, cartID, productID int64, quantity uint32) error {
-	if err := c.storage.AddProduct(ctx, cartID, productID, quantity); err != nil {
+// AddProduct with Options to a Cart. Non-empty note replaces the one of the LineItem.
+// Product must be orderable according to the ProductCatalog, if Carts have one.
+func (c *Carts) AddProduct(ctx context.Context, cartID, productID int64, options Options, quantity uint32, note string) error {
+	if err := c.authorize(ctx, cartID, RoleEditor); err != nil {
+		return err
+	}
+
+	options, err := options.normalize()
+	if err != nil {
+		return err
+	}
+
+	if note, err = validNote(note); err != nil {
+		return err
+	}
+
+	limit, err := c.orderable(ctx, productID)
+	if err != nil {
+		log.Printf("Failed to validate the Product: %d, error: %s", productID, err)
+		return err
+	}
+
+	if err := c.storage.AddProduct(c.enforceRules(ctx), cartID, productID, options, quantity, note, limit); err != nil {
 		log.Printf("Failed to add a Product: %d to the Cart: %d, error: %s", productID, cartID, err)
 		return err
 	}
 
+	c.changes.notify(cartID)
+
 	return nil
 }
 
-// DeleteProduct in a Cart.
-func (c *Carts) DeleteProduct(ctx context.Context, cartID, productID int64) error {
-	if err := c.storage.DeleteProduct(ctx, cartID, productID); err != nil {
+// DeleteProduct with Options in a Cart. Stock reserved for the Product is released.
+func (c *Carts) DeleteProduct(ctx context.Context, cartID, productID int64, options Options) error {
+	if err := c.authorize(ctx, cartID, RoleEditor); err != nil {
+		return err
+	}
+
+	options, err := options.normalize()
+	if err != nil {
+		return err
+	}
+
+	err = c.releaseAfter(ctx, cartID, false, func() error {
+		return c.storage.DeleteProduct(ctx, cartID, productID, options)
+	}, productID)
+	if err != nil {
 		log.Printf("Failed to delete the Product: %d from the Cart: %d, error: %s", productID, cartID, err)
 		return err
 	}
 
+	c.changes.notify(cartID)
+
 	return nil
 }
 
-// Cart returns Cart with provided ID.
+// SetProductQuantity of the Product with Options in a Cart. Zero quantity removes the LineItem from the Cart.
+// Product must be orderable according to the ProductCatalog, if Carts have one, unless it is removed.
+func (c *Carts) SetProductQuantity(ctx context.Context, cartID, productID int64, options Options, quantity uint32) error {
+	if err := c.authorize(ctx, cartID, RoleEditor); err != nil {
+		return err
+	}
+
+	options, err := options.normalize()
+	if err != nil {
+		return err
+	}
+
+	if quantity > 0 {
+		limit, err := c.orderable(ctx, productID)
+		if err != nil {
+			log.Printf("Failed to validate the Product: %d, error: %s", productID, err)
+			return err
+		}
+
+		if limit != 0 && quantity > limit {
+			return errQuantityTooLarge
+		}
+	}
+
+	if err := c.storage.SetProductQuantity(c.enforceRules(ctx), cartID, productID, options, quantity); err != nil {
+		log.Printf("Failed to set quantity: %d of the Product: %d in the Cart: %d, error: %s", quantity, productID, cartID, err)
+		return err
+	}
+
+	c.changes.notify(cartID)
+
+	return nil
+}
+
+// RemoveProductUnits decreases quantity of the Product with Options in a Cart. LineItem is removed when quantity reaches zero.
+func (c *Carts) RemoveProductUnits(ctx context.Context, cartID, productID int64, options Options, quantity uint32) error {
+	if err := c.authorize(ctx, cartID, RoleEditor); err != nil {
+		return err
+	}
+
+	options, err := options.normalize()
+	if err != nil {
+		return err
+	}
+
+	if err := c.storage.RemoveProductUnits(c.enforceRules(ctx), cartID, productID, options, quantity); err != nil {
+		log.Printf("Failed to remove: %d units of the Product: %d from the Cart: %d, error: %s", quantity, productID, cartID, err)
+		return err
+	}
+
+	c.changes.notify(cartID)
+
+	return nil
+}
+
+// Merge moves LineItems of the source Cart into the target Cart, for example, when a guest logs in.
+// Quantities of Products present in both Carts are combined according to the strategy.
+// The source Cart is deleted, it can be restored within the retention period. Merge returns the resulting target Cart.
+func (c *Carts) Merge(ctx context.Context, sourceCartID, targetCartID int64, strategy MergeStrategy) (Cart, error) {
+	if sourceCartID == targetCartID {
+		return Cart{}, errSameCart
+	}
+
+	if !strategy.valid() {
+		return Cart{}, errUnknownMergeStrategy
+	}
+
+	// The source Cart is deleted by the merge, so the caller must own it. A guest proves it by calling as the guest User,
+	// otherwise the merge on login must be made by a trusted service.
+	if err := c.authorize(ctx, sourceCartID, RoleOwner); err != nil {
+		return Cart{}, err
+	}
+
+	if err := c.authorize(ctx, targetCartID, RoleEditor); err != nil {
+		return Cart{}, err
+	}
+
+	cart, err := c.storage.MergeCarts(c.enforceRules(ctx), sourceCartID, targetCartID, strategy)
+	if err != nil {
+		log.Printf("Failed to merge the Cart: %d into the Cart: %d, error: %s", sourceCartID, targetCartID, err)
+		return Cart{}, err
+	}
+
+	c.changes.notify(sourceCartID)
+	c.changes.notify(targetCartID)
+
+	return cart, nil
+}
+
+// Cart returns Cart with provided ID. LineItems are priced when Carts have a PriceProvider,
+// and discounts of the applied coupons are calculated when Carts have a CouponProvider.
+// LineItems that can not be priced are returned without price, so the rest of the Cart is still readable.
+// Only members can read the Cart.
 func (c *Carts) Cart(ctx context.Context, id int64) (Cart, error) {
+	if err := c.authorize(ctx, id, RoleViewer); err != nil {
+		return Cart{}, err
+	}
+
+	return c.cart(ctx, id, true)
+}
+
+// cart returns the priced and discounted Cart. Unless partial is set, all LineItems must be priced.
+func (c *Carts) cart(ctx context.Context, id int64, partial bool) (Cart, error) {
 	cart, err := c.storage.CartByID(ctx, id)
 	if err != nil {
 		if err != errNotFound {
@@ -79,15 +282,78 @@ func (c *Carts) Cart(ctx context.Context, id int64) (Cart, error) {
 		return Cart{}, err
 	}
 
+	if err := c.price(ctx, &cart); err != nil {
+		if !partial || (err != errPriceNotFound && err != errCurrencyMismatch) {
+			log.Printf("Failed to price the Cart with ID: %d, error: %s", id, err)
+			return Cart{}, err
+		}
+	}
+
+	if err := c.discount(ctx, &cart); err != nil {
+		log.Printf("Failed to discount the Cart with ID: %d, error: %s", id, err)
+		return Cart{}, err
+	}
+
+	return cart, nil
+}
+
+// ListByUser returns a page of User Carts, newest first, and a token of the next page.
+// The token is empty when there are no more Carts. Users can only list their own Carts.
+func (c *Carts) ListByUser(ctx context.Context, userID int64, page Page) ([]Cart, string, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, "", err
+	}
+
+	beforeID, err := decodePageToken(page.Token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	size := page.size()
+
+	carts, err := c.storage.CartsByUser(ctx, userID, beforeID, size+1)
+	if err != nil {
+		log.Printf("Failed to list Carts of the User: %d, error: %s", userID, err)
+		return nil, "", err
+	}
+
+	if len(carts) <= size {
+		return carts, "", nil
+	}
+
+	carts = carts[:size]
+
+	return carts, encodePageToken(carts[size-1].ID), nil
+}
+
+// ActiveCart returns the most recently updated open Cart of a User. Users can only get their own Cart.
+func (c *Carts) ActiveCart(ctx context.Context, userID int64) (Cart, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return Cart{}, err
+	}
+
+	cart, err := c.storage.ActiveCart(ctx, userID)
+	if err != nil {
+		if err != errNotFound {
+			log.Printf("Failed to get active Cart of the User: %d, error: %s", userID, err)
+		}
+		return Cart{}, err
+	}
+
 	return cart, nil
 }
 
 // Create Cart for a User.
 func (c *Carts) Create(ctx context.Context, userID int64) (Cart, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return Cart{}, err
+	}
+
 	now := time.Now()
 
 	cart := Cart{
 		UserID:    userID,
+		Status:    StatusOpen,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -101,22 +367,68 @@ func (c *Carts) Create(ctx context.Context, userID int64) (Cart, error) {
 	return cart, nil
 }
 
-// Delete Cart by ID.
+// GetOrCreate returns the active Cart of a User. New Cart is created only if User has no open Carts.
+// It is safe to call concurrently, at most one Cart is created.
+func (c *Carts) GetOrCreate(ctx context.Context, userID int64) (Cart, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return Cart{}, err
+	}
+
+	now := time.Now()
+
+	cart := Cart{
+		UserID:    userID,
+		Status:    StatusOpen,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	cart, err := c.storage.GetOrCreateCart(ctx, cart)
+	if err != nil {
+		log.Printf("Failed to get or create a Cart for UserID: %d, error: %s", userID, err)
+		return Cart{}, err
+	}
+
+	return cart, nil
+}
+
+// Delete Cart by ID. Stock reserved for the Cart is released. The Cart is soft-deleted, it can be restored
+// within the retention period.
 func (c *Carts) Delete(ctx context.Context, cartID int64) error {
-	if err := c.storage.DeleteCart(ctx, cartID); err != nil {
-		log.Printf("Failed to delete the Cart with ID: %d, error: %s", cartID, err)
+	if err := c.authorize(ctx, cartID, RoleOwner); err != nil {
+		return err
+	}
+
+	err := c.releaseAfter(ctx, cartID, true, func() error {
+		return c.storage.DeleteCart(ctx, cartID)
+	})
+	if err != nil {
+		if err != errNotFound {
+			log.Printf("Failed to delete the Cart with ID: %d, error: %s", cartID, err)
+		}
 		return err
 	}
 
+	c.changes.notify(cartID)
+
 	return nil
 }
 
-// Empty Cart removes all previously added items.
+// Empty Cart removes all previously added items, SavedItems are kept. Stock reserved for the Cart is released.
 func (c *Carts) Empty(ctx context.Context, cartID int64) error {
-	if err := c.storage.DeleteLineItems(ctx, cartID); err != nil {
+	if err := c.authorize(ctx, cartID, RoleEditor); err != nil {
+		return err
+	}
+
+	err := c.releaseAfter(ctx, cartID, false, func() error {
+		return c.storage.DeleteLineItems(ctx, cartID)
+	})
+	if err != nil {
 		log.Printf("Failed to empty the Cart with ID: %d, error: %s", cartID, err)
 		return err
 	}
 
+	c.changes.notify(cartID)
+
 	return nil
 }
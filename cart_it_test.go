@@ -5,26 +5,33 @@ import (
 	"crypto/tls"
 	"database/sql"
 	"flag"
+	"fmt"
+	"io"
 	"log"
 	"os"
 	"strings"
 	"testing"
 	"time"
 
-	"github.com/cooldryplace/proto"
+	"github.com/cooldryplace/cart/proto"
 
 	_ "github.com/lib/pq"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
 var cartsClient proto.CartsClient
 
+// rulesProductID is sold in packs of 6 units.
+const rulesProductID int64 = 115
+
 const (
-	grpcBind  = "localhost:9001"
-	grpcCAEnv = "GRPC_CA"
+	grpcBind   = "localhost:9001"
+	grpcCAEnv  = "GRPC_CA"
+	serviceKey = "integration-tests"
 )
 
 func client(bind, caFile string) proto.CartsClient {
@@ -33,7 +40,19 @@ func client(bind, caFile string) proto.CartsClient {
 		log.Fatalf("Failed to create TLS credentials %v", err)
 	}
 
-	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	// Tests call as a trusted service, unless they pass a caller.
+	withServiceKey := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(metadata.AppendToOutgoingContext(ctx, serviceKeyHeader, serviceKey), method, req, reply, cc, opts...)
+	}
+	streamWithServiceKey := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(metadata.AppendToOutgoingContext(ctx, serviceKeyHeader, serviceKey), desc, cc, method, opts...)
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUnaryInterceptor(withServiceKey),
+		grpc.WithStreamInterceptor(streamWithServiceKey),
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -81,7 +100,10 @@ func TestMain(m *testing.M) {
 		log.Fatal(err)
 	}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(NewActorInterceptor([]string{serviceKey})),
+		grpc.StreamInterceptor(NewActorStreamInterceptor([]string{serviceKey})),
+	)
 	defer grpcServer.GracefulStop()
 
 	dbConnStr := strings.TrimSpace(os.Getenv("TEST_DB_URL"))
@@ -99,7 +121,7 @@ func TestMain(m *testing.M) {
 
 	proto.RegisterCartsServer(
 		grpcServer,
-		NewServer(New(NewStorage(db))),
+		NewServer(New(NewStorage(db), WithRules(Rules{Products: map[int64]ProductRules{rulesProductID: {MinQuantity: 6, Step: 6}}}))),
 	)
 
 	go grpcServer.Serve(lis)
@@ -257,6 +279,97 @@ func TestQuantityUpdated(t *testing.T) {
 	}
 }
 
+func setQuantity(ctx context.Context, t *testing.T, cartID, prodID int64, qtty uint32) {
+	t.Helper()
+
+	_, err := cartsClient.SetProductQuantity(ctx,
+		&proto.SetProductQuantityRequest{
+			CartId:    cartID,
+			ProductId: prodID,
+			Quantity:  qtty,
+		})
+	if err != nil {
+		t.Fatalf("Failed to set product quantity: %s", err)
+	}
+}
+
+func TestQuantitySet(t *testing.T) {
+	var (
+		ctx           = context.Background()
+		userID int64  = 11
+		prodID int64  = 101
+		qtty1  uint32 = 3
+		qtty2  uint32 = 1
+	)
+
+	cartID := createCart(ctx, t, userID)
+	defer deleteCart(ctx, t, cartID)
+
+	setQuantity(ctx, t, cartID, prodID, qtty1)
+
+	cart := cartByID(ctx, t, cartID)
+	if len(cart.Items) != 1 {
+		t.Fatal("Product was not added")
+	}
+	if cart.Items[0].Quantity != qtty1 {
+		t.Errorf("Got quantity: %d, expected: %d", cart.Items[0].Quantity, qtty1)
+	}
+
+	setQuantity(ctx, t, cartID, prodID, qtty2)
+
+	cart = cartByID(ctx, t, cartID)
+	if cart.Items[0].Quantity != qtty2 {
+		t.Errorf("Got quantity: %d, expected: %d", cart.Items[0].Quantity, qtty2)
+	}
+
+	setQuantity(ctx, t, cartID, prodID, 0)
+
+	cart = cartByID(ctx, t, cartID)
+	if len(cart.Items) != 0 {
+		t.Error("Expected zero quantity to remove the LineItem")
+	}
+}
+
+func TestProductUnitsRemoved(t *testing.T) {
+	var (
+		ctx           = context.Background()
+		userID int64  = 12
+		prodID int64  = 102
+		qtty   uint32 = 5
+	)
+
+	cartID := createCart(ctx, t, userID)
+	defer deleteCart(ctx, t, cartID)
+
+	addProduct(ctx, t, cartID, prodID, qtty)
+
+	req := &proto.RemoveProductUnitsRequest{CartId: cartID, ProductId: prodID, Quantity: 2}
+	if _, err := cartsClient.RemoveProductUnits(ctx, req); err != nil {
+		t.Fatalf("Failed to remove Product units: %s", err)
+	}
+
+	cart := cartByID(ctx, t, cartID)
+	if cart.Items[0].Quantity != qtty-2 {
+		t.Errorf("Got quantity: %d, expected: %d", cart.Items[0].Quantity, qtty-2)
+	}
+
+	req.Quantity = qtty
+	_, err := cartsClient.RemoveProductUnits(ctx, req)
+	if actual, expected := status.Code(err), codes.FailedPrecondition; actual != expected {
+		t.Errorf("Got status: %v, expected: %v", actual, expected)
+	}
+
+	req.Quantity = qtty - 2
+	if _, err := cartsClient.RemoveProductUnits(ctx, req); err != nil {
+		t.Fatalf("Failed to remove Product units: %s", err)
+	}
+
+	cart = cartByID(ctx, t, cartID)
+	if len(cart.Items) != 0 {
+		t.Error("Expected LineItem to be removed")
+	}
+}
+
 func TestProductCanBeAdded(t *testing.T) {
 	var (
 		ctx              = context.Background()
@@ -308,6 +421,537 @@ func TestProductCanBeAdded(t *testing.T) {
 	}
 }
 
+func TestListCarts(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		userID int64 = 14
+	)
+
+	var created []int64
+	for i := 0; i < 3; i++ {
+		cartID := createCart(ctx, t, userID)
+		defer deleteCart(ctx, t, cartID)
+		created = append(created, cartID)
+	}
+
+	var (
+		listed []int64
+		req    = &proto.ListCartsRequest{UserId: userID, PageSize: 2}
+	)
+
+	for {
+		resp, err := cartsClient.ListCarts(ctx, req)
+		if err != nil {
+			t.Fatalf("Failed to list Carts: %s", err)
+		}
+
+		for _, c := range resp.Carts {
+			listed = append(listed, c.Id)
+		}
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+
+	if len(listed) != len(created) {
+		t.Fatalf("Got %d Carts, expected: %d", len(listed), len(created))
+	}
+
+	for i, id := range listed {
+		if expected := created[len(created)-1-i]; id != expected {
+			t.Errorf("Got Cart ID: %d at position %d, expected: %d", id, i, expected)
+		}
+	}
+}
+
+func TestActiveCart(t *testing.T) {
+	var (
+		ctx           = context.Background()
+		userID int64  = 15
+		prodID int64  = 103
+		qtty   uint32 = 1
+	)
+
+	_, err := cartsClient.GetActiveCart(ctx, &proto.ActiveCartRequest{UserId: userID})
+	if actual, expected := status.Code(err), codes.NotFound; actual != expected {
+		t.Errorf("Got status: %v, expected: %v", actual, expected)
+	}
+
+	first := createCart(ctx, t, userID)
+	defer deleteCart(ctx, t, first)
+
+	second := createCart(ctx, t, userID)
+	defer deleteCart(ctx, t, second)
+
+	addProduct(ctx, t, first, prodID, qtty)
+
+	resp, err := cartsClient.GetActiveCart(ctx, &proto.ActiveCartRequest{UserId: userID})
+	if err != nil {
+		t.Fatalf("Failed to get active Cart: %s", err)
+	}
+
+	if resp.Cart.Id != first {
+		t.Errorf("Got active Cart ID: %d, expected: %d", resp.Cart.Id, first)
+	}
+}
+
+func TestGetOrCreateCart(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		userID int64 = 16
+		calls        = 5
+	)
+
+	var (
+		ids  = make(chan int64, calls)
+		errs = make(chan error, calls)
+	)
+
+	for i := 0; i < calls; i++ {
+		go func() {
+			resp, err := cartsClient.GetOrCreateCart(ctx, &proto.CartCreateRequest{UserId: userID})
+			if err != nil {
+				errs <- err
+				return
+			}
+			ids <- resp.Cart.Id
+		}()
+	}
+
+	var cartID int64
+
+	for i := 0; i < calls; i++ {
+		select {
+		case err := <-errs:
+			t.Fatalf("Failed to get or create a Cart: %s", err)
+		case id := <-ids:
+			if cartID == 0 {
+				cartID = id
+				defer deleteCart(ctx, t, cartID)
+			}
+			if id != cartID {
+				t.Errorf("Got Cart ID: %d, expected: %d", id, cartID)
+			}
+		}
+	}
+}
+
+func TestMergeCarts(t *testing.T) {
+	var (
+		ctx               = context.Background()
+		guestID    int64  = -17
+		userID     int64  = 17
+		sharedID   int64  = 104
+		guestOnly  int64  = 105
+		guestQtty  uint32 = 2
+		targetQtty uint32 = 3
+	)
+
+	source := createCart(ctx, t, guestID)
+
+	target := createCart(ctx, t, userID)
+	defer deleteCart(ctx, t, target)
+
+	addProduct(ctx, t, source, sharedID, guestQtty)
+	addProduct(ctx, t, source, guestOnly, guestQtty)
+	addProduct(ctx, t, target, sharedID, targetQtty)
+
+	resp, err := cartsClient.MergeCarts(ctx, &proto.MergeCartsRequest{
+		SourceCartId: source,
+		TargetCartId: target,
+		Strategy:     proto.MergeStrategy_MERGE_SUM,
+	})
+	if err != nil {
+		t.Fatalf("Failed to merge Carts: %s", err)
+	}
+
+	expected := map[int64]uint32{
+		sharedID:  guestQtty + targetQtty,
+		guestOnly: guestQtty,
+	}
+
+	if len(resp.Cart.Items) != len(expected) {
+		t.Fatalf("Got %d LineItems, expected: %d", len(resp.Cart.Items), len(expected))
+	}
+
+	for _, item := range resp.Cart.Items {
+		if item.Quantity != expected[item.ProductId] {
+			t.Errorf("Got quantity: %d of the Product: %d, expected: %d", item.Quantity, item.ProductId, expected[item.ProductId])
+		}
+	}
+
+	_, err = cartsClient.GetCart(ctx, &proto.CartRequest{Id: source})
+	if actual, expected := status.Code(err), codes.NotFound; actual != expected {
+		t.Errorf("Got status: %v, expected: %v", actual, expected)
+	}
+
+	history, err := cartsClient.GetCartHistory(ctx, &proto.CartHistoryRequest{CartId: source, PageSize: 1})
+	if err != nil {
+		t.Fatalf("Failed to get history of the merged Cart: %s", err)
+	}
+	if len(history.Entries) != 1 || history.Entries[0].Operation != string(EventCartDeleted) {
+		t.Errorf("Got latest entries: %v, expected the merged Cart deleted", history.Entries)
+	}
+
+	restored, err := cartsClient.RestoreCart(ctx, &proto.CartRequest{Id: source})
+	if err != nil {
+		t.Fatalf("Failed to restore the merged Cart: %s", err)
+	}
+	defer deleteCart(ctx, t, source)
+
+	if len(restored.Cart.Items) != 2 {
+		t.Errorf("Got %d LineItems of the restored Cart, expected: 2", len(restored.Cart.Items))
+	}
+}
+
+func TestCheckoutFreezesCart(t *testing.T) {
+	var (
+		ctx           = context.Background()
+		userID int64  = 18
+		prodID int64  = 106
+		qtty   uint32 = 1
+	)
+
+	cartID := createCart(ctx, t, userID)
+	defer deleteCart(ctx, t, cartID)
+
+	addProduct(ctx, t, cartID, prodID, qtty)
+
+	resp, err := cartsClient.BeginCheckout(ctx, &proto.CartRequest{Id: cartID})
+	if err != nil {
+		t.Fatalf("Failed to begin checkout: %s", err)
+	}
+
+	if resp.Cart.Status != proto.CartStatus_STATUS_CHECKING_OUT {
+		t.Errorf("Got status: %v, expected: %v", resp.Cart.Status, proto.CartStatus_STATUS_CHECKING_OUT)
+	}
+
+	_, err = cartsClient.AddProduct(ctx, &proto.AddProductRequest{CartId: cartID, ProductId: prodID, Quantity: qtty})
+	if actual, expected := status.Code(err), codes.FailedPrecondition; actual != expected {
+		t.Errorf("Got status: %v, expected: %v", actual, expected)
+	}
+
+	_, err = cartsClient.EmptyCart(ctx, &proto.EmptyCartRequest{CartId: cartID})
+	if actual, expected := status.Code(err), codes.FailedPrecondition; actual != expected {
+		t.Errorf("Got status: %v, expected: %v", actual, expected)
+	}
+
+	if _, err := cartsClient.ReopenCart(ctx, &proto.CartRequest{Id: cartID}); err != nil {
+		t.Fatalf("Failed to reopen the Cart: %s", err)
+	}
+
+	addProduct(ctx, t, cartID, prodID, qtty)
+
+	if _, err := cartsClient.BeginCheckout(ctx, &proto.CartRequest{Id: cartID}); err != nil {
+		t.Fatalf("Failed to begin checkout: %s", err)
+	}
+
+	resp, err = cartsClient.CompleteOrder(ctx, &proto.CartRequest{Id: cartID})
+	if err != nil {
+		t.Fatalf("Failed to complete the Order: %s", err)
+	}
+
+	if resp.Cart.Status != proto.CartStatus_STATUS_ORDERED {
+		t.Errorf("Got status: %v, expected: %v", resp.Cart.Status, proto.CartStatus_STATUS_ORDERED)
+	}
+
+	_, err = cartsClient.ReopenCart(ctx, &proto.CartRequest{Id: cartID})
+	if actual, expected := status.Code(err), codes.FailedPrecondition; actual != expected {
+		t.Errorf("Got status: %v, expected: %v", actual, expected)
+	}
+}
+
+func TestExpectedVersion(t *testing.T) {
+	var (
+		ctx           = context.Background()
+		userID int64  = 19
+		prodID int64  = 107
+		qtty   uint32 = 1
+	)
+
+	cartID := createCart(ctx, t, userID)
+	defer deleteCart(ctx, t, cartID)
+
+	version := cartByID(ctx, t, cartID).Version
+
+	req := &proto.AddProductRequest{CartId: cartID, ProductId: prodID, Quantity: qtty, ExpectedVersion: version}
+	if _, err := cartsClient.AddProduct(ctx, req); err != nil {
+		t.Fatalf("Failed to add a Product: %s", err)
+	}
+
+	cart := cartByID(ctx, t, cartID)
+	if cart.Version <= version {
+		t.Errorf("Got version: %d, expected it to be greater than: %d", cart.Version, version)
+	}
+
+	_, err := cartsClient.AddProduct(ctx, req)
+	if actual, expected := status.Code(err), codes.Aborted; actual != expected {
+		t.Errorf("Got status: %v, expected: %v", actual, expected)
+	}
+
+	if cart := cartByID(ctx, t, cartID); cart.Items[0].Quantity != qtty {
+		t.Errorf("Got quantity: %d, expected: %d", cart.Items[0].Quantity, qtty)
+	}
+}
+
+func TestIdempotentAddProduct(t *testing.T) {
+	var (
+		ctx           = context.Background()
+		userID int64  = 20
+		prodID int64  = 108
+		qtty   uint32 = 2
+	)
+
+	cartID := createCart(ctx, t, userID)
+	defer deleteCart(ctx, t, cartID)
+
+	req := &proto.AddProductRequest{
+		CartId:         cartID,
+		ProductId:      prodID,
+		Quantity:       qtty,
+		IdempotencyKey: fmt.Sprintf("add-product-%d", cartID),
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := cartsClient.AddProduct(ctx, req); err != nil {
+			t.Fatalf("Failed to add a Product: %s", err)
+		}
+	}
+
+	if cart := cartByID(ctx, t, cartID); cart.Items[0].Quantity != qtty {
+		t.Errorf("Got quantity: %d, expected: %d", cart.Items[0].Quantity, qtty)
+	}
+}
+
+func TestCartHistory(t *testing.T) {
+	var (
+		ctx          = metadata.AppendToOutgoingContext(context.Background(), "actor", "support:42")
+		userID int64 = 21
+		prodID int64 = 109
+	)
+
+	cartID := createCart(ctx, t, userID)
+	defer deleteCart(ctx, t, cartID)
+
+	addProduct(ctx, t, cartID, prodID, 2)
+	setQuantity(ctx, t, cartID, prodID, 5)
+
+	resp, err := cartsClient.GetCartHistory(ctx, &proto.CartHistoryRequest{CartId: cartID, PageSize: 2})
+	if err != nil {
+		t.Fatalf("Failed to get the Cart history: %s", err)
+	}
+
+	if len(resp.Entries) != 2 || resp.NextPageToken == "" {
+		t.Fatalf("Got %d entries and next token: %q, expected 2 entries and a token", len(resp.Entries), resp.NextPageToken)
+	}
+
+	latest := resp.Entries[0]
+	if latest.Operation != string(EventProductQuantitySet) || latest.OldQuantity != 2 || latest.NewQuantity != 5 {
+		t.Errorf("Got latest entry: %v, expected quantity set from 2 to 5", latest)
+	}
+
+	if latest.Actor != "support:42" {
+		t.Errorf("Got actor: %q, expected: %q", latest.Actor, "support:42")
+	}
+}
+
+func TestApplyCartOperations(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		userID int64 = 23
+		prodID int64 = 111
+	)
+
+	cartID := createCart(ctx, t, userID)
+	defer deleteCart(ctx, t, cartID)
+
+	req := &proto.ApplyCartOperationsRequest{
+		CartId: cartID,
+		Operations: []*proto.CartOperation{
+			{Type: proto.CartOperation_ADD, ProductId: prodID, Quantity: 2},
+			{Type: proto.CartOperation_REMOVE, ProductId: prodID, Quantity: 5},
+			{Type: proto.CartOperation_ADD, ProductId: prodID + 1, Quantity: 1},
+		},
+	}
+
+	resp, err := cartsClient.ApplyCartOperations(ctx, req)
+	if err != nil {
+		t.Fatalf("Failed to apply operations: %s", err)
+	}
+
+	if resp.Committed {
+		t.Fatal("Expected operations not to be committed")
+	}
+	if resp.Results[1].Code != int32(codes.FailedPrecondition) || !resp.Results[2].Skipped {
+		t.Errorf("Got results: %v, expected the second operation to fail and the third one to be skipped", resp.Results)
+	}
+
+	if cart := cartByID(ctx, t, cartID); len(cart.Items) != 0 {
+		t.Fatalf("Got %d items, expected the Cart to stay empty", len(cart.Items))
+	}
+
+	req.Operations[1].Quantity = 1
+
+	resp, err = cartsClient.ApplyCartOperations(ctx, req)
+	if err != nil {
+		t.Fatalf("Failed to apply operations: %s", err)
+	}
+
+	if !resp.Committed || resp.Results[1].Quantity != 1 || len(resp.Cart.Items) != 2 {
+		t.Errorf("Got response: %v, expected committed operations and 2 items", resp)
+	}
+}
+
+func TestCartSnapshot(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		userID int64 = 24
+		prodID int64 = 112
+	)
+
+	cartID := createCart(ctx, t, userID)
+	defer deleteCart(ctx, t, cartID)
+
+	addProduct(ctx, t, cartID, prodID, 2)
+
+	snapshot, err := cartsClient.SnapshotCart(ctx, &proto.CartRequest{Id: cartID})
+	if err != nil {
+		t.Fatalf("Failed to snapshot the Cart: %s", err)
+	}
+
+	addProduct(ctx, t, cartID, prodID, 1)
+
+	stored, err := cartsClient.GetSnapshot(ctx, &proto.SnapshotRequest{Id: snapshot.Id})
+	if err != nil {
+		t.Fatalf("Failed to get the Snapshot: %s", err)
+	}
+
+	if stored.Hash != snapshot.Hash {
+		t.Errorf("Got hash: %s, expected: %s", stored.Hash, snapshot.Hash)
+	}
+
+	if items := stored.Cart.Items; len(items) != 1 || items[0].Quantity != 2 {
+		t.Errorf("Got items: %v, expected 2 units of the Product: %d", items, prodID)
+	}
+}
+
+func TestSaveForLater(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		userID int64 = 25
+		prodID int64 = 113
+	)
+
+	cartID := createCart(ctx, t, userID)
+	defer deleteCart(ctx, t, cartID)
+
+	addProduct(ctx, t, cartID, prodID, 2)
+
+	resp, err := cartsClient.SaveForLater(ctx, &proto.SavedItemRequest{CartId: cartID, ProductId: prodID})
+	if err != nil {
+		t.Fatalf("Failed to save the Product for later: %s", err)
+	}
+
+	if cart := resp.Cart; len(cart.Items) != 0 || len(cart.SavedItems) != 1 || cart.SavedItems[0].Quantity != 2 {
+		t.Fatalf("Got items: %v and saved items: %v, expected 2 saved units of the Product: %d", cart.Items, cart.SavedItems, prodID)
+	}
+
+	if _, err := cartsClient.EmptyCart(ctx, &proto.EmptyCartRequest{CartId: cartID}); err != nil {
+		t.Fatalf("Failed to empty the Cart: %s", err)
+	}
+
+	resp, err = cartsClient.MoveToCart(ctx, &proto.SavedItemRequest{CartId: cartID, ProductId: prodID})
+	if err != nil {
+		t.Fatalf("Failed to move the Product to the Cart: %s", err)
+	}
+
+	if cart := resp.Cart; len(cart.Items) != 1 || cart.Items[0].Quantity != 2 || len(cart.SavedItems) != 0 {
+		t.Errorf("Got items: %v and saved items: %v, expected 2 units of the Product: %d in the Cart", cart.Items, cart.SavedItems, prodID)
+	}
+}
+
+func TestLineItemOptions(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		userID int64 = 26
+		prodID int64 = 114
+	)
+
+	cartID := createCart(ctx, t, userID)
+	defer deleteCart(ctx, t, cartID)
+
+	variants := []*proto.AddProductRequest{
+		{CartId: cartID, ProductId: prodID, Quantity: 1, Options: map[string]string{"size": "M", "color": "red"}, Note: "gift"},
+		{CartId: cartID, ProductId: prodID, Quantity: 2, Options: map[string]string{"size": "L", "color": "blue"}},
+		{CartId: cartID, ProductId: prodID, Quantity: 1, Options: map[string]string{"Color": "red", "SIZE": "M "}},
+	}
+
+	for _, req := range variants {
+		if _, err := cartsClient.AddProduct(ctx, req); err != nil {
+			t.Fatalf("Failed to add the Product: %s", err)
+		}
+	}
+
+	items := cartByID(ctx, t, cartID).Items
+	if len(items) != 2 {
+		t.Fatalf("Got %d items, expected 2 variants of the Product", len(items))
+	}
+
+	if items[0].Quantity != 2 || items[0].Options["size"] != "M" || items[0].Note != "gift" {
+		t.Errorf("Got item: %v, expected 2 units of size M with a note", items[0])
+	}
+
+	_, err := cartsClient.DelProduct(ctx, &proto.DelProductRequest{CartId: cartID, ProductId: prodID, Options: map[string]string{"size": "L", "color": "blue"}})
+	if err != nil {
+		t.Fatalf("Failed to delete the Product: %s", err)
+	}
+
+	if items := cartByID(ctx, t, cartID).Items; len(items) != 1 || items[0].Options["size"] != "M" {
+		t.Errorf("Got items: %v, expected only size M to be left", items)
+	}
+}
+
+func TestWatchCart(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		userID int64 = 22
+		prodID int64 = 110
+	)
+
+	cartID := createCart(ctx, t, userID)
+
+	stream, err := cartsClient.WatchCart(ctx, &proto.CartRequest{Id: cartID})
+	if err != nil {
+		t.Fatalf("Failed to watch the Cart: %s", err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Failed to receive the Cart: %s", err)
+	}
+	if len(resp.Cart.Items) != 0 {
+		t.Fatalf("Got %d items, expected an empty Cart", len(resp.Cart.Items))
+	}
+
+	addProduct(ctx, t, cartID, prodID, 3)
+
+	resp, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("Failed to receive the changed Cart: %s", err)
+	}
+	if len(resp.Cart.Items) != 1 || resp.Cart.Items[0].Quantity != 3 {
+		t.Errorf("Got items: %v, expected 3 units of the Product: %d", resp.Cart.Items, prodID)
+	}
+
+	deleteCart(ctx, t, cartID)
+
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Got error: %v, expected the stream to end", err)
+	}
+}
+
 func TestUnknownCartReturnsNotFound(t *testing.T) {
 	var (
 		ctx                 = context.Background()
@@ -379,4 +1023,164 @@ func TestCreatedCartCanBeDeleted(t *testing.T) {
 	if actual != expected {
 		t.Errorf("Got status: %v, expected: %v", actual, expected)
 	}
+
+	_, err = cartsClient.DeleteCart(ctx, &proto.CartDeleteRequest{Id: cartID})
+	if actual := status.Code(err); actual != expected {
+		t.Errorf("Got status of a repeated delete: %v, expected: %v", actual, expected)
+	}
+}
+
+func TestCartRulesViolation(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		userID int64 = 27
+	)
+
+	cartID := createCart(ctx, t, userID)
+	defer deleteCart(ctx, t, cartID)
+
+	_, err := cartsClient.AddProduct(ctx, &proto.AddProductRequest{CartId: cartID, ProductId: rulesProductID, Quantity: 4})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Got error: %v, expected InvalidArgument", err)
+	}
+
+	if details := status.Convert(err).Details(); len(details) != 1 {
+		t.Errorf("Got details: %v, expected a BadRequest", details)
+	}
+
+	if items := cartByID(ctx, t, cartID).Items; len(items) != 0 {
+		t.Errorf("Got items: %v, expected the violation to be rolled back", items)
+	}
+
+	addProduct(ctx, t, cartID, rulesProductID, 12)
+
+	_, err = cartsClient.RemoveProductUnits(ctx, &proto.RemoveProductUnitsRequest{CartId: cartID, ProductId: rulesProductID, Quantity: 3})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Got error: %v, expected InvalidArgument", err)
+	}
+
+	if q := cartByID(ctx, t, cartID).Items[0].Quantity; q != 12 {
+		t.Errorf("Got quantity: %d, expected: 12", q)
+	}
+}
+
+func TestRestoreCart(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		userID int64 = 28
+		prodID int64 = 116
+	)
+
+	cartID := createCart(ctx, t, userID)
+	defer deleteCart(ctx, t, cartID)
+
+	addProduct(ctx, t, cartID, prodID, 2)
+	deleteCart(ctx, t, cartID)
+
+	if _, err := cartsClient.GetCart(ctx, &proto.CartRequest{Id: cartID}); status.Code(err) != codes.NotFound {
+		t.Fatalf("Got error: %v, expected deleted Cart to be hidden", err)
+	}
+
+	resp, err := cartsClient.RestoreCart(ctx, &proto.CartRequest{Id: cartID})
+	if err != nil {
+		t.Fatalf("Failed to restore the Cart: %s", err)
+	}
+
+	if items := resp.Cart.Items; len(items) != 1 || items[0].Quantity != 2 {
+		t.Errorf("Got items: %v, expected the Cart content to be restored", items)
+	}
+
+	if _, err := cartsClient.RestoreCart(ctx, &proto.CartRequest{Id: cartID}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Got error: %v, expected FailedPrecondition for a Cart that is not deleted", err)
+	}
+}
+
+func TestCloneCart(t *testing.T) {
+	var (
+		ctx             = context.Background()
+		userID    int64 = 29
+		newUserID int64 = 30
+		prodID    int64 = 117
+	)
+
+	sourceID := createCart(ctx, t, userID)
+	defer deleteCart(ctx, t, sourceID)
+
+	addProduct(ctx, t, sourceID, prodID, 3)
+
+	resp, err := cartsClient.CloneCart(ctx, &proto.CloneCartRequest{CartId: sourceID, UserId: newUserID})
+	if err != nil {
+		t.Fatalf("Failed to clone the Cart: %s", err)
+	}
+	defer deleteCart(ctx, t, resp.Cart.Id)
+
+	if resp.Cart.Id == sourceID || resp.Cart.UserId != newUserID {
+		t.Errorf("Got Cart: %d of User: %d, expected a new Cart of User: %d", resp.Cart.Id, resp.Cart.UserId, newUserID)
+	}
+
+	if items := resp.Cart.Items; len(items) != 1 || items[0].ProductId != prodID || items[0].Quantity != 3 {
+		t.Errorf("Got items: %v, expected a copy of the source items", items)
+	}
+
+	if items := cartByID(ctx, t, sourceID).Items; len(items) != 1 {
+		t.Errorf("Got source items: %v, expected them to be kept", items)
+	}
+
+	_, err = cartsClient.CloneCart(ctx, &proto.CloneCartRequest{UserId: newUserID})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Got error: %v, expected InvalidArgument without a source", err)
+	}
+}
+
+func TestCartMembers(t *testing.T) {
+	var (
+		ctx            = context.Background()
+		ownerID  int64 = 31
+		memberID int64 = 32
+		prodID   int64 = 118
+	)
+
+	cartID := createCart(ctx, t, ownerID)
+	defer deleteCart(ctx, t, cartID)
+
+	asCaller := func(userID int64) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, callerHeader, fmt.Sprint(userID))
+	}
+
+	addReq := &proto.AddProductRequest{CartId: cartID, ProductId: prodID, Quantity: 1}
+
+	if _, err := cartsClient.AddProduct(asCaller(memberID), addReq); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Got error: %v, expected PermissionDenied for a User that is not a member", err)
+	}
+
+	_, err := cartsClient.InviteMember(asCaller(ownerID), &proto.InviteMemberRequest{CartId: cartID, UserId: memberID, Role: proto.MemberRole_MEMBER_ROLE_EDITOR})
+	if err != nil {
+		t.Fatalf("Failed to invite the member: %s", err)
+	}
+
+	if _, err := cartsClient.AddProduct(asCaller(memberID), addReq); err != nil {
+		t.Fatalf("Failed to add product as an editor: %s", err)
+	}
+
+	if _, err := cartsClient.DeleteCart(asCaller(memberID), &proto.CartDeleteRequest{Id: cartID}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Got error: %v, expected PermissionDenied for an editor deleting the Cart", err)
+	}
+
+	resp, err := cartsClient.ListMembers(asCaller(memberID), &proto.CartRequest{Id: cartID})
+	if err != nil {
+		t.Fatalf("Failed to list members: %s", err)
+	}
+
+	if ms := resp.Members; len(ms) != 2 || ms[0].UserId != ownerID || ms[0].Role != proto.MemberRole_MEMBER_ROLE_OWNER ||
+		ms[1].UserId != memberID || ms[1].Role != proto.MemberRole_MEMBER_ROLE_EDITOR {
+		t.Errorf("Got members: %v, expected the owner and the editor", ms)
+	}
+
+	if _, err := cartsClient.RemoveMember(asCaller(memberID), &proto.RemoveMemberRequest{CartId: cartID, UserId: memberID}); err != nil {
+		t.Fatalf("Failed to leave the Cart: %s", err)
+	}
+
+	if _, err := cartsClient.AddProduct(asCaller(memberID), addReq); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Got error: %v, expected PermissionDenied after leaving the Cart", err)
+	}
 }
@@ -41,7 +41,7 @@ func TestCreate(t *testing.T) {
 
 	carts := New(storage)
 
-	actual, err := carts.Create(context.Background(), expectedUserID)
+	actual, err := carts.Create(WithTrustedService(context.Background()), expectedUserID)
 	if err != nil {
 		t.Errorf("Unexpected error: %s", err)
 	}
@@ -51,36 +51,304 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestListByUser(t *testing.T) {
+	var (
+		userID int64 = 13
+		stored       = []Cart{{ID: 5}, {ID: 4}, {ID: 3}}
+	)
+
+	storage := &StorageMock{
+		CartsByUserFunc: func(ctx context.Context, uID, beforeID int64, limit int) ([]Cart, error) {
+			if uID != userID {
+				t.Errorf("Got userID: %d, expected: %d", uID, userID)
+			}
+
+			var result []Cart
+			for _, c := range stored {
+				if (beforeID == 0 || c.ID < beforeID) && len(result) < limit {
+					result = append(result, c)
+				}
+			}
+
+			return result, nil
+		},
+	}
+
+	carts := New(storage)
+
+	first, next, err := carts.ListByUser(WithTrustedService(context.Background()), userID, Page{Size: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(first) != 2 || next == "" {
+		t.Fatalf("Got %d Carts and next token: %q, expected 2 Carts and a token", len(first), next)
+	}
+
+	second, next, err := carts.ListByUser(WithTrustedService(context.Background()), userID, Page{Token: next, Size: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(second) != 1 || second[0].ID != 3 {
+		t.Errorf("Got second page: %v, expected Cart with ID: 3", second)
+	}
+
+	if next != "" {
+		t.Errorf("Got next token: %q, expected empty token on the last page", next)
+	}
+}
+
+func TestIdempotentReplay(t *testing.T) {
+	stored := []byte("stored")
+
+	storage := &StorageMock{
+		ReserveIdempotencyKeyFunc: func(ctx context.Context, key, method string, now time.Time, ttl time.Duration) ([]byte, bool, error) {
+			return stored, false, nil
+		},
+	}
+
+	carts := New(storage)
+
+	resp, err := carts.Idempotent(context.Background(), "key", "AddProduct", func() ([]byte, error) {
+		t.Error("Request applied again for a replayed idempotency key")
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if string(resp) != string(stored) {
+		t.Errorf("Got response: %q, expected: %q", resp, stored)
+	}
+}
+
+func TestIdempotentReleasesKeyOnError(t *testing.T) {
+	var released bool
+
+	storage := &StorageMock{
+		ReserveIdempotencyKeyFunc: func(ctx context.Context, key, method string, now time.Time, ttl time.Duration) ([]byte, bool, error) {
+			return nil, true, nil
+		},
+		ReleaseIdempotencyKeyFunc: func(ctx context.Context, key, method string) error {
+			released = true
+			return nil
+		},
+		SaveIdempotentResponseFunc: func(ctx context.Context, key, method string, resp []byte, expiresAt time.Time) error {
+			t.Error("Response of a failed request saved")
+			return nil
+		},
+	}
+
+	carts := New(storage)
+
+	_, err := carts.Idempotent(context.Background(), "key", "AddProduct", func() ([]byte, error) {
+		return nil, errNotFound
+	})
+	if err != errNotFound {
+		t.Errorf("Got error: %v, expected: %s", err, errNotFound)
+	}
+
+	if !released {
+		t.Error("Idempotency key not released after a failed request")
+	}
+}
+
+func TestIdempotentReservesKeyUntilSaved(t *testing.T) {
+	var saved bool
+
+	storage := &StorageMock{
+		ReserveIdempotencyKeyFunc: func(ctx context.Context, key, method string, now time.Time, ttl time.Duration) ([]byte, bool, error) {
+			if ttl != idempotencyReservationTTL {
+				t.Errorf("Got reservation TTL: %s, expected: %s", ttl, idempotencyReservationTTL)
+			}
+			return nil, true, nil
+		},
+		SaveIdempotentResponseFunc: func(ctx context.Context, key, method string, resp []byte, expiresAt time.Time) error {
+			if ttl := time.Until(expiresAt); ttl < idempotencyKeyTTL-time.Minute || ttl > idempotencyKeyTTL {
+				t.Errorf("Got response kept for: %s, expected: %s", ttl, idempotencyKeyTTL)
+			}
+			saved = true
+			return nil
+		},
+	}
+
+	if _, err := New(storage).Idempotent(context.Background(), "key", "AddProduct", func() ([]byte, error) {
+		return []byte("resp"), nil
+	}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if !saved {
+		t.Error("Response not saved")
+	}
+}
+
 // StorageMock allows you dinamically set Storage behavior.
 type StorageMock struct {
-	AddProductFunc      func(ctx context.Context, cartID, productID int64, quantity uint32) error
-	DeleteProductFunc   func(ctx context.Context, cartID, productID int64) error
-	CartByIDFunc        func(ctx context.Context, id int64) (Cart, error)
-	CreateCartFunc      func(ctx context.Context, cart Cart) (Cart, error)
-	DeleteCartFunc      func(ctx context.Context, cartID int64) error
-	DeleteLineItemsFunc func(ctx context.Context, cartID int64) error
+	AddProductFunc             func(ctx context.Context, cartID, productID int64, options Options, quantity uint32, note string, limit uint32) error
+	DeleteProductFunc          func(ctx context.Context, cartID, productID int64, options Options) error
+	SetProductQuantityFunc     func(ctx context.Context, cartID, productID int64, options Options, quantity uint32) error
+	RemoveProductUnitsFunc     func(ctx context.Context, cartID, productID int64, options Options, quantity uint32) error
+	SaveForLaterFunc           func(ctx context.Context, cartID, productID int64, options Options) error
+	MoveToCartFunc             func(ctx context.Context, cartID, productID int64, options Options, limit uint32) error
+	MergeCartsFunc             func(ctx context.Context, sourceID, targetID int64, strategy MergeStrategy) (Cart, error)
+	CartByIDFunc               func(ctx context.Context, id int64) (Cart, error)
+	CartsByUserFunc            func(ctx context.Context, userID, beforeID int64, limit int) ([]Cart, error)
+	ActiveCartFunc             func(ctx context.Context, userID int64) (Cart, error)
+	CreateCartFunc             func(ctx context.Context, cart Cart) (Cart, error)
+	GetOrCreateCartFunc        func(ctx context.Context, cart Cart) (Cart, error)
+	CloneCartFunc              func(ctx context.Context, sourceID, sourceVersion int64, cart Cart) (Cart, error)
+	DeleteCartFunc             func(ctx context.Context, cartID int64) error
+	RestoreCartFunc            func(ctx context.Context, cartID int64, deletedAfter time.Time) error
+	DeleteLineItemsFunc        func(ctx context.Context, cartID int64) error
+	ApplyOperationsFunc        func(ctx context.Context, cartID int64, ops []Operation) ([]uint32, int, error)
+	UpdateStatusFunc           func(ctx context.Context, cartID int64, from, to Status, ts time.Time) (Cart, error)
+	ReserveIdempotencyKeyFunc  func(ctx context.Context, key, method string, now time.Time, ttl time.Duration) ([]byte, bool, error)
+	SaveIdempotentResponseFunc func(ctx context.Context, key, method string, resp []byte, expiresAt time.Time) error
+	ReleaseIdempotencyKeyFunc  func(ctx context.Context, key, method string) error
+	AddCouponFunc              func(ctx context.Context, cartID int64, code string, ts time.Time) error
+	DeleteCouponFunc           func(ctx context.Context, cartID int64, code string) error
+	ReservationsFunc           func(ctx context.Context, cartID int64) (map[int64]Reservation, error)
+	SaveReservationsFunc       func(ctx context.Context, cartID int64, reservations map[int64]Reservation) error
+	ExpiredReservationsFunc    func(ctx context.Context, before time.Time, limit int) ([]reservedLineItem, error)
+	HistoryFunc                func(ctx context.Context, cartID, beforeID int64, limit int) ([]HistoryEntry, error)
+	CreateSnapshotFunc         func(ctx context.Context, snapshot Snapshot) (Snapshot, error)
+	SnapshotFunc               func(ctx context.Context, id int64) (Snapshot, error)
+	MemberRoleFunc             func(ctx context.Context, cartID, userID int64) (Role, error)
+	MembersFunc                func(ctx context.Context, cartID int64) ([]Member, error)
+	AddMemberFunc              func(ctx context.Context, cartID int64, m Member) error
+	RemoveMemberFunc           func(ctx context.Context, cartID, userID int64) error
+}
+
+func (sm *StorageMock) AddProduct(ctx context.Context, cartID, productID int64, options Options, quantity uint32, note string, limit uint32) error {
+	return sm.AddProductFunc(ctx, cartID, productID, options, quantity, note, limit)
+}
+
+func (sm *StorageMock) DeleteProduct(ctx context.Context, cartID, productID int64, options Options) error {
+	return sm.DeleteProductFunc(ctx, cartID, productID, options)
+}
+
+func (sm *StorageMock) SetProductQuantity(ctx context.Context, cartID, productID int64, options Options, quantity uint32) error {
+	return sm.SetProductQuantityFunc(ctx, cartID, productID, options, quantity)
+}
+
+func (sm *StorageMock) RemoveProductUnits(ctx context.Context, cartID, productID int64, options Options, quantity uint32) error {
+	return sm.RemoveProductUnitsFunc(ctx, cartID, productID, options, quantity)
+}
+
+func (sm *StorageMock) SaveForLater(ctx context.Context, cartID, productID int64, options Options) error {
+	return sm.SaveForLaterFunc(ctx, cartID, productID, options)
 }
 
-func (sm *StorageMock) AddProduct(ctx context.Context, cartID, productID int64, quantity uint32) error {
-	return sm.AddProductFunc(ctx, cartID, productID, quantity)
+func (sm *StorageMock) MoveToCart(ctx context.Context, cartID, productID int64, options Options, limit uint32) error {
+	return sm.MoveToCartFunc(ctx, cartID, productID, options, limit)
 }
 
-func (sm *StorageMock) DeleteProduct(ctx context.Context, cartID, productID int64) error {
-	return sm.DeleteProductFunc(ctx, cartID, productID)
+func (sm *StorageMock) MergeCarts(ctx context.Context, sourceID, targetID int64, strategy MergeStrategy) (Cart, error) {
+	return sm.MergeCartsFunc(ctx, sourceID, targetID, strategy)
 }
 
 func (sm *StorageMock) CartByID(ctx context.Context, id int64) (Cart, error) {
 	return sm.CartByIDFunc(ctx, id)
 }
 
+func (sm *StorageMock) CartsByUser(ctx context.Context, userID, beforeID int64, limit int) ([]Cart, error) {
+	return sm.CartsByUserFunc(ctx, userID, beforeID, limit)
+}
+
+func (sm *StorageMock) ActiveCart(ctx context.Context, userID int64) (Cart, error) {
+	return sm.ActiveCartFunc(ctx, userID)
+}
+
 func (sm *StorageMock) CreateCart(ctx context.Context, cart Cart) (Cart, error) {
 	return sm.CreateCartFunc(ctx, cart)
 }
 
+func (sm *StorageMock) GetOrCreateCart(ctx context.Context, cart Cart) (Cart, error) {
+	return sm.GetOrCreateCartFunc(ctx, cart)
+}
+
+func (sm *StorageMock) CloneCart(ctx context.Context, sourceID, sourceVersion int64, cart Cart) (Cart, error) {
+	return sm.CloneCartFunc(ctx, sourceID, sourceVersion, cart)
+}
+
 func (sm *StorageMock) DeleteCart(ctx context.Context, cartID int64) error {
 	return sm.DeleteCartFunc(ctx, cartID)
 }
 
+func (sm *StorageMock) RestoreCart(ctx context.Context, cartID int64, deletedAfter time.Time) error {
+	return sm.RestoreCartFunc(ctx, cartID, deletedAfter)
+}
+
 func (sm *StorageMock) DeleteLineItems(ctx context.Context, cartID int64) error {
 	return sm.DeleteLineItemsFunc(ctx, cartID)
 }
+
+func (sm *StorageMock) ApplyOperations(ctx context.Context, cartID int64, ops []Operation) ([]uint32, int, error) {
+	return sm.ApplyOperationsFunc(ctx, cartID, ops)
+}
+
+func (sm *StorageMock) UpdateStatus(ctx context.Context, cartID int64, from, to Status, ts time.Time) (Cart, error) {
+	return sm.UpdateStatusFunc(ctx, cartID, from, to, ts)
+}
+
+func (sm *StorageMock) ReserveIdempotencyKey(ctx context.Context, key, method string, now time.Time, ttl time.Duration) ([]byte, bool, error) {
+	return sm.ReserveIdempotencyKeyFunc(ctx, key, method, now, ttl)
+}
+
+func (sm *StorageMock) SaveIdempotentResponse(ctx context.Context, key, method string, resp []byte, expiresAt time.Time) error {
+	return sm.SaveIdempotentResponseFunc(ctx, key, method, resp, expiresAt)
+}
+
+func (sm *StorageMock) ReleaseIdempotencyKey(ctx context.Context, key, method string) error {
+	return sm.ReleaseIdempotencyKeyFunc(ctx, key, method)
+}
+
+func (sm *StorageMock) AddCoupon(ctx context.Context, cartID int64, code string, ts time.Time) error {
+	return sm.AddCouponFunc(ctx, cartID, code, ts)
+}
+
+func (sm *StorageMock) DeleteCoupon(ctx context.Context, cartID int64, code string) error {
+	return sm.DeleteCouponFunc(ctx, cartID, code)
+}
+
+func (sm *StorageMock) Reservations(ctx context.Context, cartID int64) (map[int64]Reservation, error) {
+	return sm.ReservationsFunc(ctx, cartID)
+}
+
+func (sm *StorageMock) SaveReservations(ctx context.Context, cartID int64, reservations map[int64]Reservation) error {
+	return sm.SaveReservationsFunc(ctx, cartID, reservations)
+}
+
+func (sm *StorageMock) ExpiredReservations(ctx context.Context, before time.Time, limit int) ([]reservedLineItem, error) {
+	return sm.ExpiredReservationsFunc(ctx, before, limit)
+}
+
+func (sm *StorageMock) History(ctx context.Context, cartID, beforeID int64, limit int) ([]HistoryEntry, error) {
+	return sm.HistoryFunc(ctx, cartID, beforeID, limit)
+}
+
+func (sm *StorageMock) CreateSnapshot(ctx context.Context, snapshot Snapshot) (Snapshot, error) {
+	return sm.CreateSnapshotFunc(ctx, snapshot)
+}
+
+func (sm *StorageMock) Snapshot(ctx context.Context, id int64) (Snapshot, error) {
+	return sm.SnapshotFunc(ctx, id)
+}
+
+func (sm *StorageMock) MemberRole(ctx context.Context, cartID, userID int64) (Role, error) {
+	return sm.MemberRoleFunc(ctx, cartID, userID)
+}
+
+func (sm *StorageMock) Members(ctx context.Context, cartID int64) ([]Member, error) {
+	return sm.MembersFunc(ctx, cartID)
+}
+
+func (sm *StorageMock) AddMember(ctx context.Context, cartID int64, m Member) error {
+	return sm.AddMemberFunc(ctx, cartID, m)
+}
+
+func (sm *StorageMock) RemoveMember(ctx context.Context, cartID, userID int64) error {
+	return sm.RemoveMemberFunc(ctx, cartID, userID)
+}
@@ -0,0 +1,111 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+var errInvalidCloneSource = errors.New("either a cart or a snapshot to clone must be provided")
+
+// Clone creates a new open Cart of the User with copies of LineItems of the source Cart, e.g. to buy them again.
+// The caller must be a member of the source Cart. Saved items, coupons and reservations are not copied. The new Cart
+// is priced as by Cart. When Carts have a ProductCatalog, Products that are not orderable make Clone fail,
+// unless skipUnavailable is set, then their LineItems are dropped and returned as skipped. LineItems over
+// the quantity limit of their Product are cut down to the limit, the missing quantity is returned as skipped.
+func (c *Carts) Clone(ctx context.Context, sourceCartID, targetUserID int64, skipUnavailable bool) (Cart, []LineItem, error) {
+	if err := authorizeUser(ctx, targetUserID); err != nil {
+		return Cart{}, nil, err
+	}
+
+	if err := c.authorize(ctx, sourceCartID, RoleViewer); err != nil {
+		return Cart{}, nil, err
+	}
+
+	source, err := c.storage.CartByID(ctx, sourceCartID)
+	if err != nil {
+		if err != errNotFound {
+			log.Printf("Failed to get the Cart with ID: %d, error: %s", sourceCartID, err)
+		}
+		return Cart{}, nil, err
+	}
+
+	return c.clone(ctx, source.ID, source.Version, source.Items, targetUserID, skipUnavailable)
+}
+
+// CloneSnapshot creates a new open Cart of the User with copies of LineItems of the Snapshot, e.g. to repeat an order.
+// The caller must be allowed to get the Snapshot. Unavailable Products are handled as by Clone.
+func (c *Carts) CloneSnapshot(ctx context.Context, snapshotID, targetUserID int64, skipUnavailable bool) (Cart, []LineItem, error) {
+	if err := authorizeUser(ctx, targetUserID); err != nil {
+		return Cart{}, nil, err
+	}
+
+	snapshot, err := c.GetSnapshot(ctx, snapshotID)
+	if err != nil {
+		return Cart{}, nil, err
+	}
+
+	// Snapshots are immutable, there is no source Version to check.
+	return c.clone(ctx, 0, 0, snapshot.Cart.Items, targetUserID, skipUnavailable)
+}
+
+// clone the LineItems into a new Cart of the User. Source Cart, unless sourceID is zero, must still have the Version.
+func (c *Carts) clone(ctx context.Context, sourceID, sourceVersion int64, items []LineItem, userID int64, skipUnavailable bool) (Cart, []LineItem, error) {
+	now := time.Now()
+
+	cart := Cart{
+		UserID:    userID,
+		Status:    StatusOpen,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	var skipped []LineItem
+
+	for _, li := range items {
+		limit, err := c.orderable(ctx, li.ProductID)
+		switch {
+		case err == errProductNotFound || err == errProductInactive:
+			if !skipUnavailable {
+				return Cart{}, nil, err
+			}
+			skipped = append(skipped, li)
+			continue
+		case err != nil:
+			log.Printf("Failed to validate the Product: %d, error: %s", li.ProductID, err)
+			return Cart{}, nil, err
+		}
+
+		if limit != 0 && li.Quantity > limit {
+			if !skipUnavailable {
+				return Cart{}, nil, errQuantityTooLarge
+			}
+			missing := li
+			missing.Quantity -= limit
+			skipped = append(skipped, missing)
+			li.Quantity = limit
+		}
+
+		cart.Items = append(cart.Items, LineItem{
+			ProductID: li.ProductID,
+			Quantity:  li.Quantity,
+			Options:   li.Options,
+			Note:      li.Note,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+
+	cart, err := c.storage.CloneCart(c.enforceRules(ctx), sourceID, sourceVersion, cart)
+	if err != nil {
+		log.Printf("Failed to clone the Cart: %d for UserID: %d, error: %s", sourceID, userID, err)
+		return Cart{}, nil, err
+	}
+
+	if cart, err = c.Cart(ctx, cart.ID); err != nil {
+		return Cart{}, nil, err
+	}
+
+	return cart, skipped, nil
+}
@@ -0,0 +1,258 @@
+package cart
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestClone(t *testing.T) {
+	catalog := StaticCatalog{
+		1: {ID: 1, Active: true},
+		2: {ID: 2, Active: true, MaxQuantity: 2},
+		3: {ID: 3},
+	}
+
+	source := Cart{
+		ID:      7,
+		Version: 3,
+		Items: []LineItem{
+			{ProductID: 1, Quantity: 1, Options: Options{"size": "M"}, Note: "gift"},
+			{ProductID: 3, Quantity: 1},
+			{ProductID: 2, Quantity: 5},
+		},
+	}
+
+	cases := []struct {
+		name            string
+		skipUnavailable bool
+		expectedItems   []LineItem
+		expectedSkipped []LineItem
+		expectedError   error
+	}{
+		{
+			name:          "Unavailable Product",
+			expectedError: errProductInactive,
+		},
+		{
+			name:            "Skip unavailable Products",
+			skipUnavailable: true,
+			expectedItems: []LineItem{
+				{ProductID: 1, Quantity: 1, Options: Options{"size": "M"}, Note: "gift"},
+				{ProductID: 2, Quantity: 2},
+			},
+			expectedSkipped: []LineItem{{ProductID: 3, Quantity: 1}, {ProductID: 2, Quantity: 3}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var cloned []LineItem
+
+			storage := &StorageMock{
+				CartByIDFunc: func(ctx context.Context, id int64) (Cart, error) {
+					if id == source.ID {
+						return source, nil
+					}
+					return Cart{ID: id, UserID: 11}, nil
+				},
+				CloneCartFunc: func(ctx context.Context, sourceID, sourceVersion int64, cart Cart) (Cart, error) {
+					if sourceID != source.ID || sourceVersion != source.Version {
+						t.Errorf("Got source: %d of version: %d, expected: %d of version: %d", sourceID, sourceVersion, source.ID, source.Version)
+					}
+					if cart.UserID != 11 || cart.Status != StatusOpen {
+						t.Errorf("Got Cart of User: %d with Status: %s, expected an open Cart of User: 11", cart.UserID, cart.Status)
+					}
+					cloned = cart.Items
+					cart.ID = 8
+					return cart, nil
+				},
+			}
+
+			cart, skipped, err := New(storage, WithCatalog(catalog)).Clone(WithTrustedService(context.Background()), source.ID, 11, c.skipUnavailable)
+			if err != c.expectedError {
+				t.Fatalf("Got error: %v, expected: %v", err, c.expectedError)
+			}
+
+			if err == nil && cart.ID != 8 {
+				t.Errorf("Got Cart: %d, expected the new Cart: 8", cart.ID)
+			}
+
+			ignoreTS := cmpopts.IgnoreFields(LineItem{}, "CreatedAt", "UpdatedAt")
+
+			if diff := cmp.Diff(c.expectedItems, cloned, ignoreTS); diff != "" {
+				t.Errorf("Cloned items mismatch (-expected +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(c.expectedSkipped, skipped); diff != "" {
+				t.Errorf("Skipped items mismatch (-expected +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCloneSnapshot(t *testing.T) {
+	storage := &StorageMock{
+		SnapshotFunc: func(ctx context.Context, id int64) (Snapshot, error) {
+			return Snapshot{ID: id, Cart: Cart{ID: 7, Version: 3, Items: []LineItem{{ProductID: 1, Quantity: 2, UnitPrice: 100}}}}, nil
+		},
+		CloneCartFunc: func(ctx context.Context, sourceID, sourceVersion int64, cart Cart) (Cart, error) {
+			if sourceID != 0 {
+				t.Errorf("Got source Cart: %d, expected Snapshot items not to depend on the Cart", sourceID)
+			}
+			if len(cart.Items) != 1 || cart.Items[0].UnitPrice != 0 {
+				t.Errorf("Got items: %v, expected a copy without the Snapshot price", cart.Items)
+			}
+			cart.ID = 8
+			return cart, nil
+		},
+		CartByIDFunc: func(ctx context.Context, id int64) (Cart, error) {
+			return Cart{ID: id}, nil
+		},
+	}
+
+	if _, _, err := New(storage).CloneSnapshot(WithTrustedService(context.Background()), 5, 11, false); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+}
+
+func TestCloneNeedsSourceMember(t *testing.T) {
+	cases := []struct {
+		name          string
+		role          Role
+		expectedError error
+	}{
+		{
+			name: "Viewer",
+			role: RoleViewer,
+		},
+		{
+			name:          "Not a member",
+			expectedError: errPermissionDenied,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var cloned bool
+
+			storage := &StorageMock{
+				MemberRoleFunc: func(ctx context.Context, cartID, userID int64) (Role, error) {
+					if cartID == 8 {
+						return RoleOwner, nil
+					}
+					if cartID != 7 || userID != 11 {
+						t.Errorf("Got role of User: %d in Cart: %d, expected User: 11 in Cart: 7", userID, cartID)
+					}
+					return c.role, nil
+				},
+				CartByIDFunc: func(ctx context.Context, id int64) (Cart, error) {
+					return Cart{ID: id, Items: []LineItem{{ProductID: 1, Quantity: 1}}}, nil
+				},
+				CloneCartFunc: func(ctx context.Context, sourceID, sourceVersion int64, cart Cart) (Cart, error) {
+					cloned = true
+					cart.ID = 8
+					return cart, nil
+				},
+			}
+
+			_, _, err := New(storage).Clone(WithCaller(context.Background(), 11), 7, 11, false)
+			if err != c.expectedError {
+				t.Fatalf("Got error: %v, expected: %v", err, c.expectedError)
+			}
+
+			if cloned != (c.expectedError == nil) {
+				t.Errorf("Got cloned: %t, expected: %t", cloned, c.expectedError == nil)
+			}
+		})
+	}
+}
+
+func TestCloneSnapshotNeedsCartMember(t *testing.T) {
+	cases := []struct {
+		name          string
+		callerID      int64
+		role          Role
+		roleErr       error
+		expectedError error
+	}{
+		{
+			name:     "Owner of a purged Cart",
+			callerID: 11,
+			roleErr:  errNotFound,
+		},
+		{
+			name:     "Member",
+			callerID: 12,
+			role:     RoleViewer,
+		},
+		{
+			name:          "Not a member",
+			callerID:      12,
+			expectedError: errPermissionDenied,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var cloned bool
+
+			storage := &StorageMock{
+				SnapshotFunc: func(ctx context.Context, id int64) (Snapshot, error) {
+					return Snapshot{ID: id, Cart: Cart{ID: 7, UserID: 11, Items: []LineItem{{ProductID: 1, Quantity: 2}}}}, nil
+				},
+				MemberRoleFunc: func(ctx context.Context, cartID, userID int64) (Role, error) {
+					if cartID == 8 {
+						return RoleOwner, nil
+					}
+					return c.role, c.roleErr
+				},
+				CloneCartFunc: func(ctx context.Context, sourceID, sourceVersion int64, cart Cart) (Cart, error) {
+					cloned = true
+					cart.ID = 8
+					return cart, nil
+				},
+				CartByIDFunc: func(ctx context.Context, id int64) (Cart, error) {
+					return Cart{ID: id}, nil
+				},
+			}
+
+			_, _, err := New(storage).CloneSnapshot(WithCaller(context.Background(), c.callerID), 5, c.callerID, false)
+			if err != c.expectedError {
+				t.Fatalf("Got error: %v, expected: %v", err, c.expectedError)
+			}
+
+			if cloned != (c.expectedError == nil) {
+				t.Errorf("Got cloned: %t, expected: %t", cloned, c.expectedError == nil)
+			}
+		})
+	}
+}
+
+func TestClonePartiallyAvailable(t *testing.T) {
+	catalog := StaticCatalog{1: {ID: 1, Active: true, MaxQuantity: 2}}
+
+	storage := &StorageMock{
+		CartByIDFunc: func(ctx context.Context, id int64) (Cart, error) {
+			return Cart{ID: id, Items: []LineItem{{ProductID: 1, Quantity: 5, Note: "gift"}}}, nil
+		},
+		CloneCartFunc: func(ctx context.Context, sourceID, sourceVersion int64, cart Cart) (Cart, error) {
+			if len(cart.Items) != 1 || cart.Items[0].Quantity != 2 {
+				t.Errorf("Got cloned items: %v, expected 2 units of the Product: 1", cart.Items)
+			}
+			cart.ID = 8
+			return cart, nil
+		},
+	}
+
+	_, skipped, err := New(storage, WithCatalog(catalog)).Clone(WithTrustedService(context.Background()), 7, 11, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if diff := cmp.Diff([]LineItem{{ProductID: 1, Quantity: 3, Note: "gift"}}, skipped); diff != "" {
+		t.Errorf("Skipped items mismatch (-expected +got):\n%s", diff)
+	}
+}
@@ -1,8 +1,13 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"net/http"
 	_ "net/http/pprof"
@@ -14,24 +19,126 @@ import (
 
 	"github.com/cooldryplace/cart"
 
-	"github.com/cooldryplace/proto"
+	"github.com/cooldryplace/cart/proto"
 
 	"contrib.go.opencensus.io/exporter/prometheus"
 	_ "github.com/lib/pq"
 	"go.opencensus.io/plugin/ocgrpc"
 	"go.opencensus.io/stats/view"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 const (
 	defaultHTTPBind = ":8000"
 	defaultGRPCBind = ":9000"
+
+	outboxInterval  = 1 * time.Second
+	janitorInterval = 1 * time.Minute
+	// reservationsInterval is how often expired stock reservations are released.
+	reservationsInterval = 1 * time.Minute
+	// abandonedInterval is how often Carts are checked for abandonment.
+	abandonedInterval = 5 * time.Minute
+	// shutdownTimeout is how long to wait for in-flight RPCs, e.g. WatchCart streams, on shutdown.
+	shutdownTimeout = 10 * time.Second
 )
 
 func healthCheck(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// staticPrices loads Product prices from JSON file, for local runs without Billing.
+func staticPrices(path string) (cart.StaticPrices, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var prices cart.StaticPrices
+	if err := json.NewDecoder(f).Decode(&prices); err != nil {
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+// staticCoupons loads coupons from JSON file, for local runs and until Marketing provides coupons,
+// e.g. {"SPRING10": {"type": "percent_off", "percent": 10}}.
+func staticCoupons(path string) (cart.StaticCoupons, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var coupons cart.StaticCoupons
+	if err := json.NewDecoder(f).Decode(&coupons); err != nil {
+		return nil, err
+	}
+
+	return coupons, nil
+}
+
+// staticStock loads stock of Products from JSON file, e.g. {"100": 25}. Stock is held in memory by each instance,
+// reservations are not shared between instances.
+func staticStock(path string) (map[int64]uint32, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var stock map[int64]uint32
+	if err := json.NewDecoder(f).Decode(&stock); err != nil {
+		return nil, err
+	}
+
+	return stock, nil
+}
+
+// loadRules loads Cart business rules from JSON file, e.g. {"maxLines": 50, "products": {"100": {"minQuantity": 12, "step": 12}}}.
+func loadRules(path string) (cart.Rules, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return cart.Rules{}, err
+	}
+	defer f.Close()
+
+	var rules cart.Rules
+	if err := json.NewDecoder(f).Decode(&rules); err != nil {
+		return cart.Rules{}, err
+	}
+
+	return rules, nil
+}
+
+// expiryPolicy reads Cart TTLs from CART_TTL_ANONYMOUS, CART_TTL_AUTHENTICATED and DELETED_CART_RETENTION env vars,
+// e.g. "72h". Carts are kept forever unless their TTL is set, deleted Carts are kept for cart.DefaultRetention.
+func expiryPolicy() (cart.ExpiryPolicy, error) {
+	policy := cart.ExpiryPolicy{Deleted: cart.DefaultRetention}
+
+	for env, ttl := range map[string]*time.Duration{
+		"CART_TTL_ANONYMOUS":     &policy.Anonymous,
+		"CART_TTL_AUTHENTICATED": &policy.Authenticated,
+		"DELETED_CART_RETENTION": &policy.Deleted,
+	} {
+		v := strings.TrimSpace(os.Getenv(env))
+		if v == "" {
+			continue
+		}
+
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return cart.ExpiryPolicy{}, fmt.Errorf("invalid %s: %s", env, err)
+		}
+
+		*ttl = d
+	}
+
+	return policy, nil
+}
+
 func main() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 
@@ -85,13 +192,177 @@ func main() {
 		log.Fatalf("Failed to establish DB connection: %s", err)
 	}
 
-	grpcServer := grpc.NewServer(grpc.StatsHandler(&ocgrpc.ServerHandler{}))
+	policy, err := expiryPolicy()
+	if err != nil {
+		log.Fatalf("Failed to configure Cart expiry: %s", err)
+	}
+
+	rules := cart.DefaultRules
+	if rulesFile := strings.TrimSpace(os.Getenv("RULES_FILE")); rulesFile != "" {
+		if rules, err = loadRules(rulesFile); err != nil {
+			log.Fatalf("Failed to load rules: %s", err)
+		}
+		log.Printf("Using Cart rules from %q", rulesFile)
+	}
+
+	opts := []cart.Option{cart.WithRules(rules), cart.WithRetention(policy.Deleted)}
+
+	if pricesFile := strings.TrimSpace(os.Getenv("PRICES_FILE")); pricesFile != "" {
+		prices, err := staticPrices(pricesFile)
+		if err != nil {
+			log.Fatalf("Failed to load prices: %s", err)
+		}
+		log.Printf("Using %d static prices from %q", len(prices), pricesFile)
+		opts = append(opts, cart.WithPrices(prices))
+	}
+
+	if couponsFile := strings.TrimSpace(os.Getenv("COUPONS_FILE")); couponsFile != "" {
+		coupons, err := staticCoupons(couponsFile)
+		if err != nil {
+			log.Fatalf("Failed to load coupons: %s", err)
+		}
+		log.Printf("Using %d static coupons from %q", len(coupons), couponsFile)
+		opts = append(opts, cart.WithCoupons(coupons))
+	}
+
+	inventoryFile := strings.TrimSpace(os.Getenv("INVENTORY_FILE"))
+	if inventoryFile != "" {
+		stock, err := staticStock(inventoryFile)
+		if err != nil {
+			log.Fatalf("Failed to load stock: %s", err)
+		}
+		log.Printf("Reserving stock of %d Products from %q on checkout", len(stock), inventoryFile)
+		opts = append(opts, cart.WithInventory(cart.NewMemoryInventory(stock)))
+	}
+
+	if productsAddr := strings.TrimSpace(os.Getenv("PRODUCTS_ADDR")); productsAddr != "" {
+		conn, err := grpc.Dial(
+			productsAddr,
+			grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})),
+			grpc.WithStatsHandler(&ocgrpc.ClientHandler{}),
+		)
+		if err != nil {
+			log.Fatalf("Failed to dial Products service: %s", err)
+		}
+		defer conn.Close()
+
+		log.Printf("Validating Products with Products service at %q", productsAddr)
+		opts = append(opts, cart.WithCatalog(cart.NewGRPCCatalog(proto.NewProductsClient(conn))))
+	}
+
+	storage := cart.NewStorage(db)
+
+	var serviceKeys []string
+	if keys := strings.TrimSpace(os.Getenv("SERVICE_KEYS")); keys != "" {
+		serviceKeys = strings.Split(keys, ",")
+		for i := range serviceKeys {
+			serviceKeys[i] = strings.TrimSpace(serviceKeys[i])
+		}
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(&ocgrpc.ServerHandler{}),
+		grpc.UnaryInterceptor(cart.NewActorInterceptor(serviceKeys)),
+		grpc.StreamInterceptor(cart.NewActorStreamInterceptor(serviceKeys)),
+	)
+
+	carts := cart.New(storage, opts...)
 
 	proto.RegisterCartsServer(
 		grpcServer,
-		cart.NewServer(cart.New(cart.NewStorage(db))),
+		cart.NewServer(carts),
 	)
 
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := make(chan struct{})
+
+	// The relay always runs, so the outbox is drained and published Events are deleted by the janitor.
+	var events io.Writer = ioutil.Discard
+
+	if eventsFile := strings.TrimSpace(os.Getenv("EVENTS_FILE")); eventsFile != "" {
+		out := os.Stdout
+		if eventsFile != "-" {
+			out, err = os.OpenFile(eventsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			if err != nil {
+				log.Fatalf("Failed to open events file: %s", err)
+			}
+			defer out.Close()
+		}
+
+		log.Printf("Publishing events to %q", eventsFile)
+		events = out
+	} else {
+		log.Printf("Discarding events, EVENTS_FILE is not set")
+	}
+
+	go func() {
+		defer close(relayDone)
+		cart.NewRelay(storage, cart.NewWriterPublisher(events), outboxInterval).Run(relayCtx)
+	}()
+
+	listenerCtx, stopListener := context.WithCancel(context.Background())
+	listenerDone := make(chan struct{})
+
+	// Changes made by other instances reach local watchers only with NOTIFY_CHANGES set.
+	if os.Getenv("NOTIFY_CHANGES") != "" {
+		listener, err := cart.NewChangeListener(dbConnStr, carts)
+		if err != nil {
+			log.Fatalf("Failed to listen for Cart changes: %s", err)
+		}
+
+		go func() {
+			defer close(listenerDone)
+			listener.Run(listenerCtx)
+		}()
+	} else {
+		close(listenerDone)
+	}
+
+	janitorCtx, stopJanitor := context.WithCancel(context.Background())
+	janitorDone := make(chan struct{})
+
+	// The janitor always runs to delete expired idempotency keys, Carts are expired only with a TTL.
+	log.Printf("Expiring Carts of guests after %s, of Users after %s and deleted Carts after %s, zero keeps Carts forever",
+		policy.Anonymous, policy.Authenticated, policy.Deleted)
+
+	go func() {
+		defer close(janitorDone)
+		cart.NewJanitor(storage, policy, janitorInterval).Run(janitorCtx)
+	}()
+
+	sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+	sweeperDone := make(chan struct{})
+
+	// Stock reserved for Carts that are not ordered in time is released.
+	if inventoryFile != "" {
+		go func() {
+			defer close(sweeperDone)
+			cart.NewReservationSweeper(carts, reservationsInterval).Run(sweeperCtx)
+		}()
+	} else {
+		close(sweeperDone)
+	}
+
+	detectorCtx, stopDetector := context.WithCancel(context.Background())
+	detectorDone := make(chan struct{})
+
+	// Abandoned Carts are reported with EventCartAbandoned delivered by the relay.
+	if abandonedAfter := strings.TrimSpace(os.Getenv("ABANDONED_AFTER")); abandonedAfter != "" {
+		threshold, err := time.ParseDuration(abandonedAfter)
+		if err != nil {
+			log.Fatalf("Invalid ABANDONED_AFTER: %s", err)
+		}
+
+		log.Printf("Reporting Carts idle for %s as abandoned", threshold)
+
+		go func() {
+			defer close(detectorDone)
+			cart.NewAbandonmentDetector(storage, threshold, abandonedInterval).Run(detectorCtx)
+		}()
+	} else {
+		close(detectorDone)
+	}
+
 	var (
 		errChan    = make(chan error, 2)
 		signalChan = make(chan os.Signal, 1)
@@ -116,6 +387,9 @@ func main() {
 	if err := view.Register(ocgrpc.DefaultClientViews...); err != nil {
 		log.Fatalf("Failed to register default client views: %s", err)
 	}
+	if err := view.Register(cart.JanitorViews...); err != nil {
+		log.Fatalf("Failed to register janitor views: %s", err)
+	}
 
 	http.Handle("/metrics", pe)
 	http.HandleFunc("/health", healthCheck)
@@ -134,5 +408,30 @@ func main() {
 		log.Println("Interrupt received. Graceful shutdown.")
 	}
 
-	grpcServer.GracefulStop()
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		grpcServer.Stop()
+	}
+
+	stopListener()
+	<-listenerDone
+
+	stopJanitor()
+	<-janitorDone
+
+	stopSweeper()
+	<-sweeperDone
+
+	stopDetector()
+	<-detectorDone
+
+	stopRelay()
+	<-relayDone
 }
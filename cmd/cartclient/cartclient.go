@@ -9,15 +9,17 @@ import (
 	"strings"
 	"time"
 
-	"github.com/cooldryplace/proto"
+	"github.com/cooldryplace/cart/proto"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 )
 
 const (
-	grpcCAEnv   = "GRPC_CA"
-	defaultBind = "localhost:9000"
+	grpcCAEnv    = "GRPC_CA"
+	defaultBind  = "localhost:9000"
+	callerHeader = "caller-id"
 )
 
 var userID = time.Now().Unix() * -1
@@ -77,6 +79,9 @@ func main() {
 	ctx, cancel = context.WithTimeout(context.Background(), 1*time.Minute)
 	defer cancel()
 
+	// Act as the guest User that owns the Cart, as a gateway does for authenticated Users.
+	ctx = metadata.AppendToOutgoingContext(ctx, callerHeader, fmt.Sprint(userID))
+
 	resp, err := client.CreateCart(ctx, &proto.CartCreateRequest{UserId: userID})
 	if err != nil {
 		log.Fatalf("Failed to create a Cart: %s", err)
@@ -0,0 +1,71 @@
+package cart
+
+import "context"
+
+type contextKey int
+
+const (
+	expectedVersionKey contextKey = iota
+	actorKey
+	rulesKey
+	callerKey
+	trustedKey
+)
+
+// WithExpectedVersion returns a context that makes Cart mutations succeed only when
+// the Cart has the provided Version. Zero version matches any Cart Version.
+func WithExpectedVersion(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, expectedVersionKey, version)
+}
+
+// expectedVersion returns Cart Version expected by the caller or zero if any Version is fine.
+func expectedVersion(ctx context.Context) int64 {
+	v, _ := ctx.Value(expectedVersionKey).(int64)
+	return v
+}
+
+// WithActor returns a context that attributes Cart changes to the actor, e.g. a User or a support agent.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// actor returns who makes the change or empty string if it is unknown.
+func actor(ctx context.Context) string {
+	a, _ := ctx.Value(actorKey).(string)
+	return a
+}
+
+// withRules returns a context that makes Storage check Cart mutations against the Rules, nil Rules disable the checks.
+func withRules(ctx context.Context, r *Rules) context.Context {
+	return context.WithValue(ctx, rulesKey, r)
+}
+
+// rulesFrom returns Rules to check Cart mutations against or nil if there are none.
+func rulesFrom(ctx context.Context) *Rules {
+	r, _ := ctx.Value(rulesKey).(*Rules)
+	return r
+}
+
+// WithCaller returns a context that identifies the User making the call. Reads and changes of a Cart check that
+// the caller is a member of the Cart with a sufficient Role, calls on behalf of a User check that the caller is the User.
+func WithCaller(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, callerKey, userID)
+}
+
+// caller returns ID of the User making the call and false if the caller is unknown.
+func caller(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(callerKey).(int64)
+	return id, ok
+}
+
+// WithTrustedService returns a context of a call made by an authenticated service rather than a User.
+// Calls of trusted services without a caller are not checked against Cart members.
+func WithTrustedService(ctx context.Context) context.Context {
+	return context.WithValue(ctx, trustedKey, true)
+}
+
+// trusted reports whether the call is made by an authenticated service.
+func trusted(ctx context.Context) bool {
+	t, _ := ctx.Value(trustedKey).(bool)
+	return t
+}
@@ -0,0 +1,144 @@
+package cart
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// EventType describes what happened to a Cart.
+type EventType string
+
+// Cart event types.
+const (
+	EventCartCreated          EventType = "cart.created"
+	EventCartDeleted          EventType = "cart.deleted"
+	EventCartRestored         EventType = "cart.restored"
+	EventCartEmptied          EventType = "cart.emptied"
+	EventCartMerged           EventType = "cart.merged"
+	EventCartAbandoned        EventType = "cart.abandoned"
+	EventCartStatusChanged    EventType = "cart.status_changed"
+	EventProductAdded         EventType = "product.added"
+	EventProductDeleted       EventType = "product.deleted"
+	EventProductQuantitySet   EventType = "product.quantity_set"
+	EventProductUnitsRemoved  EventType = "product.units_removed"
+	EventProductSavedForLater EventType = "product.saved_for_later"
+	EventProductMovedToCart   EventType = "product.moved_to_cart"
+	EventCouponApplied        EventType = "coupon.applied"
+	EventCouponRemoved        EventType = "coupon.removed"
+	EventMemberAdded          EventType = "member.added"
+	EventMemberRemoved        EventType = "member.removed"
+)
+
+// Event is a change of a Cart. Events are written to the outbox in the same transaction as the change
+// and published to other microservices, e.g. Business Analytics, by Relay.
+// Fields not related to the Type are empty.
+type Event struct {
+	ID           int64       `json:"id"`
+	Type         EventType   `json:"type"`
+	CartID       int64       `json:"cartId"`
+	UserID       int64       `json:"userId,omitempty"`
+	ProductID    int64       `json:"productId,omitempty"`
+	Options      Options     `json:"options,omitempty"`
+	Quantity     uint32      `json:"quantity,omitempty"`
+	SourceCartID int64       `json:"sourceCartId,omitempty"`
+	Status       Status      `json:"status,omitempty"`
+	Coupon       string      `json:"coupon,omitempty"`
+	Role         Role        `json:"role,omitempty"`
+	Items        []EventItem `json:"items,omitempty"`
+	CreatedAt    time.Time   `json:"createdAt"`
+}
+
+// EventItem is a LineItem of the Cart content carried by an Event, e.g. by EventCartAbandoned.
+type EventItem struct {
+	ProductID int64   `json:"productId"`
+	Options   Options `json:"options,omitempty"`
+	Quantity  uint32  `json:"quantity"`
+}
+
+// Publisher delivers Events to their consumers. Events are delivered at least once,
+// Publish is called again with the same Events if it fails.
+type Publisher interface {
+	Publish(ctx context.Context, events []Event) error
+}
+
+// WriterPublisher writes Events as JSON lines, for example, to stdout or a file for local runs.
+type WriterPublisher struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewWriterPublisher returns Publisher that writes Events to w.
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{enc: json.NewEncoder(w)}
+}
+
+// Publish writes Events one per line.
+func (wp *WriterPublisher) Publish(ctx context.Context, events []Event) error {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	for _, e := range events {
+		if err := wp.enc.Encode(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// outboxBatch limits number of Events published at once.
+const outboxBatch = 100
+
+type outbox interface {
+	PublishEvents(ctx context.Context, limit int, ts time.Time, publish func([]Event) error) (int, error)
+}
+
+// Relay publishes Events written to the outbox.
+type Relay struct {
+	outbox    outbox
+	publisher Publisher
+	interval  time.Duration
+}
+
+// NewRelay returns Relay that checks the outbox of the Storage every interval.
+func NewRelay(s *Storage, p Publisher, interval time.Duration) *Relay {
+	return &Relay{outbox: s, publisher: p, interval: interval}
+}
+
+// flush publishes pending Events until the outbox is empty.
+func (r *Relay) flush(ctx context.Context) error {
+	for {
+		n, err := r.outbox.PublishEvents(ctx, outboxBatch, time.Now(), func(events []Event) error {
+			return r.publisher.Publish(ctx, events)
+		})
+		if err != nil {
+			return err
+		}
+
+		if n < outboxBatch {
+			return nil
+		}
+	}
+}
+
+// Run publishes Events until the context is canceled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.flush(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to publish events: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
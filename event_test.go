@@ -0,0 +1,107 @@
+package cart
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWriterPublisher(t *testing.T) {
+	var (
+		buf bytes.Buffer
+		ts  = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	events := []Event{
+		{ID: 1, Type: EventCartCreated, CartID: 5, UserID: 13, CreatedAt: ts},
+		{ID: 2, Type: EventProductAdded, CartID: 5, ProductID: 7, Quantity: 2, CreatedAt: ts},
+		{ID: 3, Type: EventCartAbandoned, CartID: 5, UserID: 13, Items: []EventItem{{ProductID: 7, Quantity: 2}}, CreatedAt: ts},
+	}
+
+	if err := NewWriterPublisher(&buf).Publish(context.Background(), events); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := `{"id":1,"type":"cart.created","cartId":5,"userId":13,"createdAt":"2019-07-01T12:00:00Z"}
+{"id":2,"type":"product.added","cartId":5,"productId":7,"quantity":2,"createdAt":"2019-07-01T12:00:00Z"}
+{"id":3,"type":"cart.abandoned","cartId":5,"userId":13,"items":[{"productId":7,"quantity":2}],"createdAt":"2019-07-01T12:00:00Z"}
+`
+
+	if actual := buf.String(); actual != expected {
+		t.Errorf("Got output:\n%s\nexpected:\n%s", actual, expected)
+	}
+}
+
+type outboxMock struct {
+	pending []Event
+	calls   int
+}
+
+func (om *outboxMock) PublishEvents(ctx context.Context, limit int, ts time.Time, publish func([]Event) error) (int, error) {
+	om.calls++
+
+	n := limit
+	if n > len(om.pending) {
+		n = len(om.pending)
+	}
+
+	if err := publish(om.pending[:n]); err != nil {
+		return 0, err
+	}
+
+	om.pending = om.pending[n:]
+
+	return n, nil
+}
+
+type publisherFunc func(ctx context.Context, events []Event) error
+
+func (f publisherFunc) Publish(ctx context.Context, events []Event) error {
+	return f(ctx, events)
+}
+
+func TestRelayFlush(t *testing.T) {
+	outbox := &outboxMock{pending: make([]Event, outboxBatch+1)}
+
+	var published int
+
+	relay := &Relay{
+		outbox: outbox,
+		publisher: publisherFunc(func(ctx context.Context, events []Event) error {
+			published += len(events)
+			return nil
+		}),
+	}
+
+	if err := relay.flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if published != outboxBatch+1 || outbox.calls != 2 {
+		t.Errorf("Got %d Events published in %d batches, expected: %d in 2 batches", published, outbox.calls, outboxBatch+1)
+	}
+}
+
+func TestRelayKeepsEventsOnFailure(t *testing.T) {
+	var (
+		outbox  = &outboxMock{pending: make([]Event, 3)}
+		failure = errors.New("publisher is down")
+	)
+
+	relay := &Relay{
+		outbox: outbox,
+		publisher: publisherFunc(func(ctx context.Context, events []Event) error {
+			return failure
+		}),
+	}
+
+	if err := relay.flush(context.Background()); err != failure {
+		t.Fatalf("Got error: %v, expected: %s", err, failure)
+	}
+
+	if len(outbox.pending) != 3 {
+		t.Errorf("Got %d pending Events, expected: 3", len(outbox.pending))
+	}
+}
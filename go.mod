@@ -4,10 +4,10 @@ go 1.12
 
 require (
 	contrib.go.opencensus.io/exporter/prometheus v0.1.0
-	github.com/cooldryplace/proto v0.0.0-20190721160137-4a8afa2ee71d
 	github.com/golang/protobuf v1.3.2
 	github.com/google/go-cmp v0.3.0
 	github.com/lib/pq v1.2.0
 	go.opencensus.io v0.22.0
+	google.golang.org/genproto v0.0.0-20190425155659-357c62f0e4bb
 	google.golang.org/grpc v1.22.0
 )
@@ -0,0 +1,52 @@
+package cart
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// HistoryEntry is a change of a Cart made by the Actor. Operation names match Event types.
+// Quantities are set for changes of a Product quantity. Detail describes the change when there is no Product,
+// e.g. new Cart Status or a coupon code. Empty Actor means the caller was not identified.
+type HistoryEntry struct {
+	ID          int64
+	CartID      int64
+	Actor       string
+	Operation   EventType
+	ProductID   int64
+	OldQuantity uint32
+	NewQuantity uint32
+	Detail      string
+	CreatedAt   time.Time
+}
+
+// History returns a page of the Cart changes, newest first, and a token of the next page.
+// The token is empty when there are no more changes. History is kept after the Cart is deleted.
+// Only members can read the history.
+func (c *Carts) History(ctx context.Context, cartID int64, page Page) ([]HistoryEntry, string, error) {
+	if err := c.authorize(ctx, cartID, RoleViewer); err != nil {
+		return nil, "", err
+	}
+
+	beforeID, err := decodePageToken(page.Token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	size := page.size()
+
+	entries, err := c.storage.History(ctx, cartID, beforeID, size+1)
+	if err != nil {
+		log.Printf("Failed to get history of the Cart: %d, error: %s", cartID, err)
+		return nil, "", err
+	}
+
+	if len(entries) <= size {
+		return entries, "", nil
+	}
+
+	entries = entries[:size]
+
+	return entries, encodePageToken(entries[size-1].ID), nil
+}
@@ -0,0 +1,50 @@
+package cart
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	var (
+		cartID int64 = 5
+		stored       = []HistoryEntry{{ID: 30}, {ID: 20}, {ID: 10}}
+	)
+
+	storage := &StorageMock{
+		HistoryFunc: func(ctx context.Context, id, beforeID int64, limit int) ([]HistoryEntry, error) {
+			if id != cartID {
+				t.Errorf("Got cartID: %d, expected: %d", id, cartID)
+			}
+
+			var result []HistoryEntry
+			for _, h := range stored {
+				if (beforeID == 0 || h.ID < beforeID) && len(result) < limit {
+					result = append(result, h)
+				}
+			}
+
+			return result, nil
+		},
+	}
+
+	carts := New(storage)
+
+	first, next, err := carts.History(WithTrustedService(context.Background()), cartID, Page{Size: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(first) != 2 || next == "" {
+		t.Fatalf("Got %d entries and next token: %q, expected 2 entries and a token", len(first), next)
+	}
+
+	second, next, err := carts.History(WithTrustedService(context.Background()), cartID, Page{Token: next, Size: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(second) != 1 || second[0].ID != 10 || next != "" {
+		t.Errorf("Got second page: %v and next token: %q, expected entry with ID: 10 and no token", second, next)
+	}
+}
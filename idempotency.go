@@ -0,0 +1,62 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+)
+
+const (
+	// idempotencyKeyTTL is how long responses are kept for replays.
+	idempotencyKeyTTL = 24 * time.Hour
+	// idempotencyReservationTTL is how long a key is reserved for a request in progress. If the response is lost,
+	// e.g. the service stops before saving it, the request can be made again with the key after the reservation expires.
+	idempotencyReservationTTL = time.Minute
+)
+
+var errRequestInProgress = errors.New("request with the same idempotency key is in progress")
+
+// idempotencyScope returns the caller idempotency keys of the call belong to. Keys of trusted services,
+// calling without a caller, share the empty scope.
+func idempotencyScope(ctx context.Context) string {
+	if callerID, ok := caller(ctx); ok {
+		return strconv.FormatInt(callerID, 10)
+	}
+
+	return ""
+}
+
+// Idempotent calls f at most once for the key and method and stores its response.
+// Later calls with the same key and method return the stored response without calling f,
+// until the key expires. Keys are scoped by the caller, so callers can not replay responses of each other.
+// If f fails, the key is released so the request can be retried. Requests in progress are rejected with errRequestInProgress.
+func (c *Carts) Idempotent(ctx context.Context, key, method string, f func() ([]byte, error)) ([]byte, error) {
+	resp, reserved, err := c.storage.ReserveIdempotencyKey(ctx, key, method, time.Now(), idempotencyReservationTTL)
+	if err != nil {
+		if err != errRequestInProgress {
+			log.Printf("Failed to reserve idempotency key: %q of the method: %s, error: %s", key, method, err)
+		}
+		return nil, err
+	}
+
+	if !reserved {
+		return resp, nil
+	}
+
+	resp, err = f()
+	if err != nil {
+		if err := c.storage.ReleaseIdempotencyKey(ctx, key, method); err != nil {
+			log.Printf("Failed to release idempotency key: %q of the method: %s, error: %s", key, method, err)
+		}
+		return nil, err
+	}
+
+	if err := c.storage.SaveIdempotentResponse(ctx, key, method, resp, time.Now().Add(idempotencyKeyTTL)); err != nil {
+		// The change is already applied, a retry will be rejected as in progress until the reservation expires.
+		log.Printf("Failed to save response for idempotency key: %q of the method: %s, error: %s", key, method, err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,400 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// reservationTTL is how long stock is held for a Cart being checked out, unless reservations are renewed.
+const reservationTTL = 15 * time.Minute
+
+// expiredReservationsBatch limits number of expired reservations released at once.
+const expiredReservationsBatch = 100
+
+var (
+	errOutOfStock           = errors.New("product is out of stock")
+	errReservationNotFound  = errors.New("reservation not found")
+	errReservationExpired   = errors.New("stock reservation of the cart expired")
+	errReservationsDisabled = errors.New("inventory reservations are not configured")
+	errCartNotCheckingOut   = errors.New("cart is not being checked out")
+)
+
+// Reservation of Product stock held until ExpiresAt.
+type Reservation struct {
+	ID        string
+	ExpiresAt time.Time
+}
+
+// reservedLineItem is a LineItem reservation of a Cart.
+type reservedLineItem struct {
+	CartID    int64
+	ProductID int64
+	Reservation
+}
+
+// Inventory holds Product stock for Carts being checked out.
+type Inventory interface {
+	// Reserve quantity of the Product for ttl. Returns errOutOfStock when there is not enough stock.
+	Reserve(ctx context.Context, productID int64, quantity uint32, ttl time.Duration) (Reservation, error)
+	// Renew extends the reservation for ttl from now. Returns errReservationNotFound for expired reservations.
+	Renew(ctx context.Context, id string, ttl time.Duration) (Reservation, error)
+	// Release returns reserved stock. Releasing unknown or expired reservation is not an error.
+	Release(ctx context.Context, id string) error
+}
+
+// WithInventory sets Inventory used to reserve stock for Carts being checked out.
+// Without it checkout does not reserve stock.
+func WithInventory(inv Inventory) Option {
+	return func(c *Carts) {
+		c.inventory = inv
+	}
+}
+
+type memoryReservation struct {
+	productID int64
+	quantity  uint32
+	expiresAt time.Time
+}
+
+// MemoryInventory is an in-memory Inventory for tests and local runs. Products without stock can not be reserved.
+type MemoryInventory struct {
+	mu           sync.Mutex
+	seq          int64
+	stock        map[int64]uint32
+	reservations map[string]memoryReservation
+}
+
+// NewMemoryInventory returns Inventory with the stock of Products.
+func NewMemoryInventory(stock map[int64]uint32) *MemoryInventory {
+	s := make(map[int64]uint32, len(stock))
+	for id, q := range stock {
+		s[id] = q
+	}
+
+	return &MemoryInventory{
+		stock:        s,
+		reservations: make(map[string]memoryReservation),
+	}
+}
+
+// available returns stock of the Product that is not reserved. Expired reservations are dropped.
+func (mi *MemoryInventory) available(productID int64, now time.Time) uint32 {
+	available := mi.stock[productID]
+
+	for id, r := range mi.reservations {
+		if !now.Before(r.expiresAt) {
+			delete(mi.reservations, id)
+			continue
+		}
+		if r.productID == productID {
+			available -= r.quantity
+		}
+	}
+
+	return available
+}
+
+// Reserve quantity of the Product.
+func (mi *MemoryInventory) Reserve(ctx context.Context, productID int64, quantity uint32, ttl time.Duration) (Reservation, error) {
+	mi.mu.Lock()
+	defer mi.mu.Unlock()
+
+	now := time.Now()
+
+	if mi.available(productID, now) < quantity {
+		return Reservation{}, errOutOfStock
+	}
+
+	mi.seq++
+	id := strconv.FormatInt(mi.seq, 10)
+
+	r := memoryReservation{productID: productID, quantity: quantity, expiresAt: now.Add(ttl)}
+	mi.reservations[id] = r
+
+	return Reservation{ID: id, ExpiresAt: r.expiresAt}, nil
+}
+
+// Renew the reservation.
+func (mi *MemoryInventory) Renew(ctx context.Context, id string, ttl time.Duration) (Reservation, error) {
+	mi.mu.Lock()
+	defer mi.mu.Unlock()
+
+	now := time.Now()
+
+	r, ok := mi.reservations[id]
+	if !ok || !now.Before(r.expiresAt) {
+		delete(mi.reservations, id)
+		return Reservation{}, errReservationNotFound
+	}
+
+	r.expiresAt = now.Add(ttl)
+	mi.reservations[id] = r
+
+	return Reservation{ID: id, ExpiresAt: r.expiresAt}, nil
+}
+
+// Release the reservation.
+func (mi *MemoryInventory) Release(ctx context.Context, id string) error {
+	mi.mu.Lock()
+	defer mi.mu.Unlock()
+
+	delete(mi.reservations, id)
+
+	return nil
+}
+
+// byProduct combines quantities and totals of LineItems of the same Product with different Options.
+// Stock is reserved per Product, LineItems of the Product share the reservation. Promotions apply per Product too.
+func byProduct(items []LineItem) []LineItem {
+	var (
+		products []LineItem
+		index    = make(map[int64]int, len(items))
+	)
+
+	for _, li := range items {
+		if i, ok := index[li.ProductID]; ok {
+			products[i].Quantity += li.Quantity
+			products[i].LineTotal += li.LineTotal
+			continue
+		}
+
+		index[li.ProductID] = len(products)
+		products = append(products, LineItem{
+			ProductID:     li.ProductID,
+			Quantity:      li.Quantity,
+			UnitPrice:     li.UnitPrice,
+			LineTotal:     li.LineTotal,
+			ReservationID: li.ReservationID,
+		})
+	}
+
+	return products
+}
+
+// reserve stock for all LineItems of the Cart. Reservations made before a failure are released.
+func (c *Carts) reserve(ctx context.Context, cart Cart) (map[int64]Reservation, error) {
+	reservations := make(map[int64]Reservation, len(cart.Items))
+
+	for _, li := range byProduct(cart.Items) {
+		r, err := c.inventory.Reserve(ctx, li.ProductID, li.Quantity, reservationTTL)
+		if err != nil {
+			c.release(ctx, cart.ID, reservations)
+			if err != errOutOfStock {
+				err = fmt.Errorf("failed to reserve the Product: %d, error: %s", li.ProductID, err)
+			}
+			return nil, err
+		}
+		reservations[li.ProductID] = r
+	}
+
+	return reservations, nil
+}
+
+// release reservations in the Inventory. Failures are only logged, reservations expire anyway.
+func (c *Carts) release(ctx context.Context, cartID int64, reservations map[int64]Reservation) {
+	for productID, r := range reservations {
+		if err := c.inventory.Release(ctx, r.ID); err != nil {
+			log.Printf("Failed to release reservation: %q of the Product: %d in the Cart: %d, error: %s", r.ID, productID, cartID, err)
+		}
+	}
+}
+
+// releaseAfter calls f and releases reservations of the Cart LineItems that were held before the call.
+// LineItems of all Products are released when productIDs are not provided.
+// When clearItems is set the released reservations are also removed from LineItems kept by f.
+func (c *Carts) releaseAfter(ctx context.Context, cartID int64, clearItems bool, f func() error, productIDs ...int64) error {
+	if c.inventory == nil {
+		return f()
+	}
+
+	reservations, err := c.storage.Reservations(ctx, cartID)
+	if err != nil {
+		return err
+	}
+
+	if len(productIDs) > 0 {
+		selected := make(map[int64]Reservation, len(productIDs))
+		for _, id := range productIDs {
+			if r, ok := reservations[id]; ok {
+				selected[id] = r
+			}
+		}
+		reservations = selected
+	}
+
+	if err := f(); err != nil {
+		return err
+	}
+
+	if len(reservations) == 0 {
+		return nil
+	}
+
+	c.release(ctx, cartID, reservations)
+
+	if !clearItems {
+		return nil
+	}
+
+	cleared := make(map[int64]Reservation, len(reservations))
+	for id := range reservations {
+		cleared[id] = Reservation{}
+	}
+
+	if err := c.storage.SaveReservations(ctx, cartID, cleared); err != nil {
+		log.Printf("Failed to clear reservations of the Cart: %d, error: %s", cartID, err)
+	}
+
+	return nil
+}
+
+// RenewReservations extends stock reservations of the Cart being checked out.
+// Expired reservations are made again if there is still enough stock.
+func (c *Carts) RenewReservations(ctx context.Context, cartID int64) (Cart, error) {
+	if err := c.authorize(ctx, cartID, RoleOwner); err != nil {
+		return Cart{}, err
+	}
+
+	if c.inventory == nil {
+		return Cart{}, errReservationsDisabled
+	}
+
+	cart, err := c.storage.CartByID(ctx, cartID)
+	if err != nil {
+		return Cart{}, err
+	}
+
+	if cart.Status != StatusCheckingOut {
+		return Cart{}, errCartNotCheckingOut
+	}
+
+	renewed := make(map[int64]Reservation, len(cart.Items))
+
+	for _, li := range byProduct(cart.Items) {
+		var r Reservation
+
+		err := errReservationNotFound
+		if li.ReservationID != "" {
+			r, err = c.inventory.Renew(ctx, li.ReservationID, reservationTTL)
+		}
+		if err == errReservationNotFound {
+			r, err = c.inventory.Reserve(ctx, li.ProductID, li.Quantity, reservationTTL)
+		}
+		if err != nil {
+			log.Printf("Failed to renew reservation of the Product: %d in the Cart: %d, error: %s", li.ProductID, cartID, err)
+			return Cart{}, err
+		}
+		renewed[li.ProductID] = r
+	}
+
+	if err := c.storage.SaveReservations(ctx, cartID, renewed); err != nil {
+		log.Printf("Failed to save reservations of the Cart: %d, error: %s", cartID, err)
+		return Cart{}, err
+	}
+
+	return c.storage.CartByID(ctx, cartID)
+}
+
+// ReleaseExpiredReservations releases a batch of reservations that expired before now
+// and removes them from LineItems. It returns the number of released reservations.
+func (c *Carts) ReleaseExpiredReservations(ctx context.Context, now time.Time) (int, error) {
+	if c.inventory == nil {
+		return 0, nil
+	}
+
+	expired, err := c.storage.ExpiredReservations(ctx, now, expiredReservationsBatch)
+	if err != nil {
+		log.Printf("Failed to get expired reservations, error: %s", err)
+		return 0, err
+	}
+
+	byCart := make(map[int64]map[int64]Reservation)
+	for _, r := range expired {
+		if byCart[r.CartID] == nil {
+			byCart[r.CartID] = make(map[int64]Reservation)
+		}
+		byCart[r.CartID][r.ProductID] = r.Reservation
+	}
+
+	for cartID, reservations := range byCart {
+		c.release(ctx, cartID, reservations)
+
+		for id := range reservations {
+			reservations[id] = Reservation{}
+		}
+
+		if err := c.storage.SaveReservations(ctx, cartID, reservations); err != nil {
+			log.Printf("Failed to clear expired reservations of the Cart: %d, error: %s", cartID, err)
+			return 0, err
+		}
+	}
+
+	return len(expired), nil
+}
+
+// ReservationSweeper releases stock reservations of Carts that were not ordered before the reservations expired.
+type ReservationSweeper struct {
+	carts    *Carts
+	interval time.Duration
+}
+
+// NewReservationSweeper returns ReservationSweeper that releases expired reservations of the Carts every interval.
+func NewReservationSweeper(c *Carts, interval time.Duration) *ReservationSweeper {
+	return &ReservationSweeper{carts: c, interval: interval}
+}
+
+// sweep releases expired reservations in batches until there are no more of them.
+func (rs *ReservationSweeper) sweep(ctx context.Context, now time.Time) error {
+	for {
+		n, err := rs.carts.ReleaseExpiredReservations(ctx, now)
+		if err != nil {
+			return err
+		}
+
+		if n < expiredReservationsBatch {
+			return nil
+		}
+	}
+}
+
+// Run releases expired reservations until the context is canceled.
+func (rs *ReservationSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(rs.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := rs.sweep(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to release expired reservations: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// unreserve removes reservations from LineItems of the Cart.
+func unreserve(cart *Cart) {
+	for i := range cart.Items {
+		cart.Items[i].ReservationID = ""
+		cart.Items[i].ReservedUntil = time.Time{}
+	}
+}
+
+// reserved checks that all LineItems of the Cart have reservations valid at now.
+func reserved(cart Cart, now time.Time) error {
+	for _, li := range cart.Items {
+		if li.ReservationID == "" || !now.Before(li.ReservedUntil) {
+			return errReservationExpired
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package cart
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryInventory(t *testing.T) {
+	var (
+		ctx       = WithTrustedService(context.Background())
+		inventory = NewMemoryInventory(map[int64]uint32{1: 3})
+	)
+
+	r, err := inventory.Reserve(ctx, 1, 2, time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if _, err := inventory.Reserve(ctx, 1, 2, time.Minute); err != errOutOfStock {
+		t.Errorf("Got error: %v, expected: %s", err, errOutOfStock)
+	}
+
+	if _, err := inventory.Reserve(ctx, 2, 1, time.Minute); err != errOutOfStock {
+		t.Errorf("Got error: %v, expected: %s for Product without stock", err, errOutOfStock)
+	}
+
+	if err := inventory.Release(ctx, r.ID); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if _, err := inventory.Reserve(ctx, 1, 3, time.Minute); err != nil {
+		t.Errorf("Got error: %s, expected released stock to be available", err)
+	}
+
+	if _, err := inventory.Renew(ctx, r.ID, time.Minute); err != errReservationNotFound {
+		t.Errorf("Got error: %v, expected: %s", err, errReservationNotFound)
+	}
+
+}
+
+func TestBeginCheckoutReservesStock(t *testing.T) {
+	var (
+		cartID int64 = 7
+		items        = []LineItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 1}}
+		saved  map[int64]Reservation
+	)
+
+	storage := &StorageMock{
+		CartByIDFunc: func(ctx context.Context, id int64) (Cart, error) {
+			return Cart{ID: id, Status: StatusOpen, Items: items}, nil
+		},
+		UpdateStatusFunc: func(ctx context.Context, id int64, from, to Status, ts time.Time) (Cart, error) {
+			return Cart{ID: id, Status: to, Items: items}, nil
+		},
+		SaveReservationsFunc: func(ctx context.Context, id int64, reservations map[int64]Reservation) error {
+			saved = reservations
+			return nil
+		},
+	}
+
+	carts := New(storage, WithInventory(NewMemoryInventory(map[int64]uint32{1: 2, 2: 1})))
+
+	cart, err := carts.BeginCheckout(WithTrustedService(context.Background()), cartID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(saved) != len(items) {
+		t.Fatalf("Got %d saved reservations, expected: %d", len(saved), len(items))
+	}
+
+	if err := reserved(cart, time.Now()); err != nil {
+		t.Errorf("Got error: %s, expected all LineItems to be reserved", err)
+	}
+}
+
+func TestBeginCheckoutOutOfStock(t *testing.T) {
+	var (
+		cartID    int64 = 7
+		items           = []LineItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 5}}
+		inventory       = NewMemoryInventory(map[int64]uint32{1: 2, 2: 1})
+		statuses  []Status
+	)
+
+	storage := &StorageMock{
+		CartByIDFunc: func(ctx context.Context, id int64) (Cart, error) {
+			return Cart{ID: id, Status: StatusOpen, Items: items}, nil
+		},
+		UpdateStatusFunc: func(ctx context.Context, id int64, from, to Status, ts time.Time) (Cart, error) {
+			statuses = append(statuses, to)
+			return Cart{ID: id, Status: to, Items: items}, nil
+		},
+		SaveReservationsFunc: func(ctx context.Context, id int64, reservations map[int64]Reservation) error {
+			t.Error("Reservations saved for a Cart that is out of stock")
+			return nil
+		},
+	}
+
+	carts := New(storage, WithInventory(inventory))
+
+	ctx := WithExpectedVersion(WithTrustedService(context.Background()), 3)
+
+	if _, err := carts.BeginCheckout(ctx, cartID); err != errOutOfStock {
+		t.Fatalf("Got error: %v, expected: %s", err, errOutOfStock)
+	}
+
+	if len(statuses) != 2 || statuses[1] != StatusOpen {
+		t.Errorf("Got status changes: %v, expected the Cart to be reopened", statuses)
+	}
+
+	if _, err := inventory.Reserve(context.Background(), 1, 2, time.Minute); err != nil {
+		t.Errorf("Got error: %s, expected stock of the Product to be released", err)
+	}
+}
+
+func TestReleaseExpiredReservations(t *testing.T) {
+	var (
+		ctx       = WithTrustedService(context.Background())
+		inventory = NewMemoryInventory(map[int64]uint32{1: 2})
+		cleared   = make(map[int64]map[int64]Reservation)
+	)
+
+	r, err := inventory.Reserve(ctx, 1, 2, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	storage := &StorageMock{
+		ExpiredReservationsFunc: func(ctx context.Context, before time.Time, limit int) ([]reservedLineItem, error) {
+			if len(cleared) > 0 {
+				return nil, nil
+			}
+			return []reservedLineItem{{CartID: 7, ProductID: 1, Reservation: r}}, nil
+		},
+		SaveReservationsFunc: func(ctx context.Context, cartID int64, reservations map[int64]Reservation) error {
+			cleared[cartID] = reservations
+			return nil
+		},
+	}
+
+	carts := New(storage, WithInventory(inventory))
+
+	if err := NewReservationSweeper(carts, time.Minute).sweep(ctx, time.Now()); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if res, ok := cleared[7][1]; !ok || res != (Reservation{}) {
+		t.Errorf("Got reservations: %v, expected reservation of the Product: 1 to be cleared", cleared[7])
+	}
+
+	if _, err := inventory.Reserve(ctx, 1, 2, time.Minute); err != nil {
+		t.Errorf("Got error: %s, expected released stock to be available", err)
+	}
+}
+
+func TestReservationSweeperBatches(t *testing.T) {
+	var calls int
+
+	storage := &StorageMock{
+		ExpiredReservationsFunc: func(ctx context.Context, before time.Time, limit int) ([]reservedLineItem, error) {
+			calls++
+			if calls > 1 {
+				return nil, nil
+			}
+
+			expired := make([]reservedLineItem, limit)
+			for i := range expired {
+				expired[i] = reservedLineItem{CartID: int64(i), ProductID: 1, Reservation: Reservation{ID: "r"}}
+			}
+			return expired, nil
+		},
+		SaveReservationsFunc: func(ctx context.Context, cartID int64, reservations map[int64]Reservation) error {
+			return nil
+		},
+	}
+
+	carts := New(storage, WithInventory(NewMemoryInventory(nil)))
+
+	if err := NewReservationSweeper(carts, time.Minute).sweep(context.Background(), time.Now()); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("Got %d batches, expected the sweep to continue after a full batch", calls)
+	}
+}
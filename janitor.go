@@ -0,0 +1,170 @@
+package cart
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+)
+
+// janitorBatch limits number of Carts, idempotency keys or Events deleted in one transaction.
+const janitorBatch = 100
+
+// publishedEventsTTL is how long published Events are kept in the outbox, e.g. to investigate delivery issues.
+const publishedEventsTTL = 24 * time.Hour
+
+// janitorActor is recorded in the history of expired Carts.
+const janitorActor = "janitor"
+
+var (
+	expiredCarts     = stats.Int64("janitor/expired_carts", "Number of expired Carts deleted", stats.UnitDimensionless)
+	expiredLineItems = stats.Int64("janitor/expired_line_items", "Number of LineItems deleted with expired Carts", stats.UnitDimensionless)
+	expiredKeys      = stats.Int64("janitor/expired_idempotency_keys", "Number of expired idempotency keys deleted", stats.UnitDimensionless)
+	expiredEvents    = stats.Int64("janitor/expired_events", "Number of published Events deleted from the outbox", stats.UnitDimensionless)
+
+	// JanitorViews of deleted Carts, LineItems, idempotency keys and Events, they must be registered to be exported.
+	JanitorViews = []*view.View{
+		{
+			Name:        expiredCarts.Name(),
+			Description: expiredCarts.Description(),
+			Measure:     expiredCarts,
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        expiredLineItems.Name(),
+			Description: expiredLineItems.Description(),
+			Measure:     expiredLineItems,
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        expiredKeys.Name(),
+			Description: expiredKeys.Description(),
+			Measure:     expiredKeys,
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        expiredEvents.Name(),
+			Description: expiredEvents.Description(),
+			Measure:     expiredEvents,
+			Aggregation: view.Sum(),
+		},
+	}
+)
+
+// ExpiryPolicy defines how long Carts are kept after their last change. Carts of guests, with non-positive UserID,
+// are Anonymous. Deleted is how long soft-deleted Carts are kept before they are purged. Zero TTL keeps Carts forever.
+// Carts in checkout never expire, unless they are deleted.
+type ExpiryPolicy struct {
+	Anonymous     time.Duration
+	Authenticated time.Duration
+	Deleted       time.Duration
+}
+
+// expiresBefore returns the time Carts with the TTL must be changed after to be kept, it is zero when they are kept forever.
+func expiresBefore(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+
+	return now.Add(-ttl)
+}
+
+type expiringStorage interface {
+	DeleteExpiredCarts(ctx context.Context, anonymousBefore, authenticatedBefore, deletedBefore time.Time, limit int) (int, int, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time, limit int) (int, error)
+	DeletePublishedEvents(ctx context.Context, publishedBefore time.Time, limit int) (int, error)
+}
+
+// Janitor deletes expired Carts with their LineItems, expired idempotency keys and published Events.
+type Janitor struct {
+	storage  expiringStorage
+	policy   ExpiryPolicy
+	interval time.Duration
+}
+
+// NewJanitor returns Janitor that deletes Carts of the Storage expired according to the policy every interval.
+func NewJanitor(s *Storage, p ExpiryPolicy, interval time.Duration) *Janitor {
+	return &Janitor{storage: s, policy: p, interval: interval}
+}
+
+// sweep deletes expired Carts, idempotency keys and published Events.
+func (j *Janitor) sweep(ctx context.Context, now time.Time) error {
+	if err := j.sweepCarts(ctx, now); err != nil {
+		return err
+	}
+
+	err := sweepBatches(ctx, expiredKeys, func(limit int) (int, error) {
+		return j.storage.DeleteExpiredIdempotencyKeys(ctx, now, limit)
+	})
+	if err != nil {
+		return err
+	}
+
+	return sweepBatches(ctx, expiredEvents, func(limit int) (int, error) {
+		return j.storage.DeletePublishedEvents(ctx, now.Add(-publishedEventsTTL), limit)
+	})
+}
+
+// sweepCarts deletes expired Carts in batches until there are no more of them.
+func (j *Janitor) sweepCarts(ctx context.Context, now time.Time) error {
+	var (
+		anonymous     = expiresBefore(now, j.policy.Anonymous)
+		authenticated = expiresBefore(now, j.policy.Authenticated)
+		deleted       = expiresBefore(now, j.policy.Deleted)
+	)
+
+	if anonymous.IsZero() && authenticated.IsZero() && deleted.IsZero() {
+		return nil
+	}
+
+	for {
+		carts, items, err := j.storage.DeleteExpiredCarts(ctx, anonymous, authenticated, deleted, janitorBatch)
+		if err != nil {
+			return err
+		}
+
+		stats.Record(ctx, expiredCarts.M(int64(carts)), expiredLineItems.M(int64(items)))
+
+		if carts < janitorBatch {
+			return nil
+		}
+	}
+}
+
+// sweepBatches calls del with janitorBatch until it deletes less than a batch and records the deleted rows to m.
+func sweepBatches(ctx context.Context, m *stats.Int64Measure, del func(limit int) (int, error)) error {
+	for {
+		n, err := del(janitorBatch)
+		if err != nil {
+			return err
+		}
+
+		stats.Record(ctx, m.M(int64(n)))
+
+		if n < janitorBatch {
+			return nil
+		}
+	}
+}
+
+// Run deletes expired Carts, idempotency keys and published Events until the context is canceled.
+func (j *Janitor) Run(ctx context.Context) {
+	ctx = WithActor(ctx, janitorActor)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.sweep(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to delete expired carts, idempotency keys or events: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
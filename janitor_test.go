@@ -0,0 +1,131 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type expiringStorageMock struct {
+	expired             int
+	anonymousBefore     time.Time
+	authenticatedBefore time.Time
+	deletedBefore       time.Time
+	calls               int
+	expiredKeys         int
+	keysBefore          time.Time
+	keyCalls            int
+	eventsBefore        time.Time
+	err                 error
+}
+
+func (m *expiringStorageMock) DeleteExpiredCarts(ctx context.Context, anonymousBefore, authenticatedBefore, deletedBefore time.Time, limit int) (int, int, error) {
+	m.calls++
+	m.anonymousBefore, m.authenticatedBefore, m.deletedBefore = anonymousBefore, authenticatedBefore, deletedBefore
+
+	if m.err != nil {
+		return 0, 0, m.err
+	}
+
+	n := limit
+	if n > m.expired {
+		n = m.expired
+	}
+	m.expired -= n
+
+	return n, n * 2, nil
+}
+
+func (m *expiringStorageMock) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time, limit int) (int, error) {
+	m.keyCalls++
+	m.keysBefore = now
+
+	n := limit
+	if n > m.expiredKeys {
+		n = m.expiredKeys
+	}
+	m.expiredKeys -= n
+
+	return n, nil
+}
+
+func (m *expiringStorageMock) DeletePublishedEvents(ctx context.Context, publishedBefore time.Time, limit int) (int, error) {
+	m.eventsBefore = publishedBefore
+	return 0, nil
+}
+
+func TestJanitorSweep(t *testing.T) {
+	var (
+		now     = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+		storage = &expiringStorageMock{expired: janitorBatch + 1}
+		janitor = &Janitor{storage: storage, policy: ExpiryPolicy{Anonymous: 24 * time.Hour, Deleted: DefaultRetention}}
+	)
+
+	if err := janitor.sweep(context.Background(), now); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if storage.expired != 0 || storage.calls != 2 {
+		t.Errorf("Got %d Carts left after %d batches, expected all Carts deleted in 2 batches", storage.expired, storage.calls)
+	}
+
+	if expected := now.Add(-24 * time.Hour); !storage.anonymousBefore.Equal(expected) {
+		t.Errorf("Got anonymous expiry time: %s, expected: %s", storage.anonymousBefore, expected)
+	}
+
+	if expected := now.Add(-DefaultRetention); !storage.deletedBefore.Equal(expected) {
+		t.Errorf("Got deleted expiry time: %s, expected: %s", storage.deletedBefore, expected)
+	}
+
+	if !storage.authenticatedBefore.IsZero() {
+		t.Errorf("Got authenticated expiry time: %s, expected Carts of Users to be kept", storage.authenticatedBefore)
+	}
+}
+
+func TestJanitorSweepWithoutTTL(t *testing.T) {
+	storage := &expiringStorageMock{expired: 1}
+
+	if err := (&Janitor{storage: storage}).sweep(context.Background(), time.Now()); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if storage.calls != 0 {
+		t.Errorf("Got %d calls, expected no Carts to expire", storage.calls)
+	}
+}
+
+func TestJanitorSweepError(t *testing.T) {
+	var (
+		expected = errors.New("storage error")
+		storage  = &expiringStorageMock{expired: 1, err: expected}
+		janitor  = &Janitor{storage: storage, policy: ExpiryPolicy{Authenticated: time.Hour}}
+	)
+
+	if err := janitor.sweep(context.Background(), time.Now()); err != expected {
+		t.Errorf("Got error: %v, expected: %v", err, expected)
+	}
+}
+
+func TestJanitorSweepIdempotencyKeys(t *testing.T) {
+	var (
+		now     = time.Now()
+		storage = &expiringStorageMock{expiredKeys: 2*janitorBatch + 1}
+	)
+
+	if err := (&Janitor{storage: storage}).sweep(context.Background(), now); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if storage.expiredKeys != 0 || storage.keyCalls != 3 {
+		t.Errorf("Got %d keys left after %d batches, expected all keys deleted in 3 batches", storage.expiredKeys, storage.keyCalls)
+	}
+
+	if !storage.keysBefore.Equal(now) {
+		t.Errorf("Got keys expired before: %s, expected: %s", storage.keysBefore, now)
+	}
+
+	if expected := now.Add(-publishedEventsTTL); !storage.eventsBefore.Equal(expected) {
+		t.Errorf("Got events published before: %s, expected: %s", storage.eventsBefore, expected)
+	}
+}
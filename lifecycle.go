@@ -0,0 +1,177 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+var (
+	errCartNotOpen       = errors.New("cart is not open")
+	errInvalidTransition = errors.New("invalid cart status transition")
+)
+
+// Status of a Cart in its lifecycle. Only open Carts can be modified.
+type Status string
+
+// Cart statuses.
+const (
+	StatusOpen        Status = "open"
+	StatusCheckingOut Status = "checking_out"
+	StatusOrdered     Status = "ordered"
+	StatusAbandoned   Status = "abandoned"
+)
+
+// transitions lists statuses reachable from each Status.
+var transitions = map[Status][]Status{
+	StatusOpen:        {StatusCheckingOut, StatusAbandoned},
+	StatusCheckingOut: {StatusOrdered, StatusOpen, StatusAbandoned},
+	StatusAbandoned:   {StatusOpen},
+}
+
+func canTransition(from, to Status) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// transition moves the Cart to a new Status if the lifecycle allows it.
+func (c *Carts) transition(ctx context.Context, cartID int64, to Status) (Cart, error) {
+	if err := c.authorize(ctx, cartID, RoleOwner); err != nil {
+		return Cart{}, err
+	}
+
+	cart, err := c.storage.CartByID(ctx, cartID)
+	if err != nil {
+		return Cart{}, err
+	}
+
+	if !canTransition(cart.Status, to) {
+		return Cart{}, errInvalidTransition
+	}
+
+	cart, err = c.storage.UpdateStatus(ctx, cartID, cart.Status, to, time.Now())
+	if err != nil {
+		return Cart{}, err
+	}
+
+	c.changes.notify(cartID)
+
+	return cart, nil
+}
+
+// BeginCheckout freezes an open Cart while the Order is being placed.
+// When Carts have Inventory, stock is reserved for all LineItems. If any Product can not be reserved,
+// the Cart is reopened and no stock stays reserved.
+func (c *Carts) BeginCheckout(ctx context.Context, cartID int64) (Cart, error) {
+	cart, err := c.transition(ctx, cartID, StatusCheckingOut)
+	if err != nil {
+		log.Printf("Failed to begin checkout of the Cart: %d, error: %s", cartID, err)
+		return Cart{}, err
+	}
+
+	if c.inventory == nil {
+		return cart, nil
+	}
+
+	reservations, err := c.reserve(ctx, cart)
+	if err == nil {
+		err = c.storage.SaveReservations(ctx, cartID, reservations)
+		if err != nil {
+			c.release(ctx, cartID, reservations)
+		}
+	}
+	if err != nil {
+		log.Printf("Failed to reserve stock for the Cart: %d, error: %s", cartID, err)
+
+		// Cart version was changed by this call, the one expected by the caller does not apply.
+		_, rerr := c.storage.UpdateStatus(WithExpectedVersion(ctx, 0), cartID, StatusCheckingOut, StatusOpen, time.Now())
+		if rerr != nil {
+			log.Printf("Failed to reopen the Cart: %d, error: %s", cartID, rerr)
+		}
+		c.changes.notify(cartID)
+
+		return Cart{}, err
+	}
+
+	for i, li := range cart.Items {
+		r := reservations[li.ProductID]
+		cart.Items[i].ReservationID = r.ID
+		cart.Items[i].ReservedUntil = r.ExpiresAt
+	}
+
+	return cart, nil
+}
+
+// CompleteOrder marks the Cart being checked out as ordered. Ordered Carts can not be changed anymore.
+// When Carts have Inventory, all LineItems must have valid stock reservations, they are kept for the Order.
+func (c *Carts) CompleteOrder(ctx context.Context, cartID int64) (Cart, error) {
+	// Callers that are not allowed to complete the Order must not learn about the Cart reservations.
+	if err := c.authorize(ctx, cartID, RoleOwner); err != nil {
+		return Cart{}, err
+	}
+
+	if c.inventory != nil {
+		cart, err := c.storage.CartByID(ctx, cartID)
+		if err == nil {
+			err = reserved(cart, time.Now())
+		}
+		if err != nil {
+			log.Printf("Failed to complete the Order of the Cart: %d, error: %s", cartID, err)
+			return Cart{}, err
+		}
+	}
+
+	cart, err := c.transition(ctx, cartID, StatusOrdered)
+	if err != nil {
+		log.Printf("Failed to complete the Order of the Cart: %d, error: %s", cartID, err)
+		return Cart{}, err
+	}
+
+	return cart, nil
+}
+
+// Reopen returns the Cart being checked out or abandoned to the open state. Reserved stock is released.
+func (c *Carts) Reopen(ctx context.Context, cartID int64) (Cart, error) {
+	var cart Cart
+
+	err := c.releaseAfter(ctx, cartID, true, func() (err error) {
+		cart, err = c.transition(ctx, cartID, StatusOpen)
+		return err
+	})
+	if err != nil {
+		log.Printf("Failed to reopen the Cart: %d, error: %s", cartID, err)
+		return Cart{}, err
+	}
+
+	if c.inventory != nil {
+		unreserve(&cart)
+	}
+
+	return cart, nil
+}
+
+// Abandon marks the Cart User is not going to order. Reserved stock is released.
+func (c *Carts) Abandon(ctx context.Context, cartID int64) (Cart, error) {
+	var cart Cart
+
+	err := c.releaseAfter(ctx, cartID, true, func() (err error) {
+		cart, err = c.transition(ctx, cartID, StatusAbandoned)
+		return err
+	})
+	if err != nil {
+		log.Printf("Failed to abandon the Cart: %d, error: %s", cartID, err)
+		return Cart{}, err
+	}
+
+	if c.inventory != nil {
+		unreserve(&cart)
+	}
+
+	return cart, nil
+}
@@ -0,0 +1,96 @@
+package cart
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCanTransition(t *testing.T) {
+	cases := []struct {
+		from     Status
+		to       Status
+		expected bool
+	}{
+		{from: StatusOpen, to: StatusCheckingOut, expected: true},
+		{from: StatusOpen, to: StatusAbandoned, expected: true},
+		{from: StatusOpen, to: StatusOrdered, expected: false},
+		{from: StatusCheckingOut, to: StatusOrdered, expected: true},
+		{from: StatusCheckingOut, to: StatusOpen, expected: true},
+		{from: StatusCheckingOut, to: StatusAbandoned, expected: true},
+		{from: StatusAbandoned, to: StatusOpen, expected: true},
+		{from: StatusAbandoned, to: StatusCheckingOut, expected: false},
+		{from: StatusOrdered, to: StatusOpen, expected: false},
+		{from: StatusOrdered, to: StatusAbandoned, expected: false},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.from)+"->"+string(c.to), func(t *testing.T) {
+			if actual := canTransition(c.from, c.to); actual != c.expected {
+				t.Errorf("Got: %t, expected: %t", actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestBeginCheckout(t *testing.T) {
+	var cartID int64 = 99
+
+	storage := &StorageMock{
+		CartByIDFunc: func(ctx context.Context, id int64) (Cart, error) {
+			return Cart{ID: id, Status: StatusOpen}, nil
+		},
+		UpdateStatusFunc: func(ctx context.Context, id int64, from, to Status, ts time.Time) (Cart, error) {
+			if from != StatusOpen {
+				t.Errorf("Got from status: %s, expected: %s", from, StatusOpen)
+			}
+			if to != StatusCheckingOut {
+				t.Errorf("Got to status: %s, expected: %s", to, StatusCheckingOut)
+			}
+			return Cart{ID: id, Status: to}, nil
+		},
+	}
+
+	cart, err := New(storage).BeginCheckout(WithTrustedService(context.Background()), cartID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if cart.Status != StatusCheckingOut {
+		t.Errorf("Got status: %s, expected: %s", cart.Status, StatusCheckingOut)
+	}
+}
+
+func TestOrderedCartCanNotBeReopened(t *testing.T) {
+	storage := &StorageMock{
+		CartByIDFunc: func(ctx context.Context, id int64) (Cart, error) {
+			return Cart{ID: id, Status: StatusOrdered}, nil
+		},
+		UpdateStatusFunc: func(ctx context.Context, id int64, from, to Status, ts time.Time) (Cart, error) {
+			t.Error("Status of the ordered Cart was updated")
+			return Cart{}, nil
+		},
+	}
+
+	if _, err := New(storage).Reopen(WithTrustedService(context.Background()), 99); err != errInvalidTransition {
+		t.Errorf("Got error: %v, expected: %v", err, errInvalidTransition)
+	}
+}
+
+func TestCompleteOrderNeedsOwner(t *testing.T) {
+	storage := &StorageMock{
+		MemberRoleFunc: func(ctx context.Context, cartID, userID int64) (Role, error) {
+			return RoleEditor, nil
+		},
+		CartByIDFunc: func(ctx context.Context, id int64) (Cart, error) {
+			t.Error("Cart read before the caller was authorized")
+			return Cart{ID: id, Status: StatusCheckingOut}, nil
+		},
+	}
+
+	carts := New(storage, WithInventory(NewMemoryInventory(map[int64]uint32{1: 1})))
+
+	if _, err := carts.CompleteOrder(WithCaller(context.Background(), 2), 1); err != errPermissionDenied {
+		t.Errorf("Got error: %v, expected: %v", err, errPermissionDenied)
+	}
+}
@@ -0,0 +1,155 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+var (
+	errPermissionDenied = errors.New("caller is not allowed to change the cart")
+	errUnauthenticated  = errors.New("caller is not identified")
+	errUnknownRole      = errors.New("unknown member role")
+	errMemberNotFound   = errors.New("member not found")
+	errOwnerMembership  = errors.New("membership of the cart owner can not be changed")
+)
+
+// Role of a Cart member defines what the member can do with the Cart.
+type Role string
+
+// Member roles. Viewers can only read the Cart, editors can change its content,
+// owners can also delete and check out the Cart and manage its members.
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleOwner  Role = "owner"
+)
+
+// roleRanks orders Roles, every Role allows everything a lower one does.
+var roleRanks = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+func (r Role) valid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// allows reports whether the Role is sufficient for actions that need the other one.
+func (r Role) allows(need Role) bool {
+	return r.valid() && roleRanks[r] >= roleRanks[need]
+}
+
+// Member of a shared Cart. User of the Cart is always its owner, other Users become members when they are invited.
+type Member struct {
+	UserID    int64
+	Role      Role
+	CreatedAt time.Time
+}
+
+// authorize the caller to read or change the Cart with the Role, e.g. RoleViewer for reads. Calls of trusted services
+// without a caller are not checked, other calls without a caller are rejected.
+func (c *Carts) authorize(ctx context.Context, cartID int64, need Role) error {
+	callerID, ok := caller(ctx)
+	if !ok {
+		return authorizeService(ctx)
+	}
+
+	role, err := c.storage.MemberRole(ctx, cartID, callerID)
+	if err != nil {
+		if err != errNotFound {
+			log.Printf("Failed to get the Role of the User: %d in the Cart: %d, error: %s", callerID, cartID, err)
+		}
+		return err
+	}
+
+	if !role.allows(need) {
+		return errPermissionDenied
+	}
+
+	return nil
+}
+
+// authorizeUser checks that the caller acts on behalf of itself, e.g. creates or lists its own Carts. Trusted services
+// without a caller can act on behalf of any User.
+func authorizeUser(ctx context.Context, userID int64) error {
+	callerID, ok := caller(ctx)
+	if !ok {
+		return authorizeService(ctx)
+	}
+
+	if callerID != userID {
+		return errPermissionDenied
+	}
+
+	return nil
+}
+
+// authorizeService allows calls without a caller only to trusted services.
+func authorizeService(ctx context.Context) error {
+	if !trusted(ctx) {
+		return errUnauthenticated
+	}
+
+	return nil
+}
+
+// InviteMember to a Cart with the Role or change the Role of an existing member. Only owners can manage members.
+func (c *Carts) InviteMember(ctx context.Context, cartID, userID int64, role Role) (Member, error) {
+	if !role.valid() {
+		return Member{}, errUnknownRole
+	}
+
+	if err := c.authorize(ctx, cartID, RoleOwner); err != nil {
+		return Member{}, err
+	}
+
+	m := Member{UserID: userID, Role: role, CreatedAt: time.Now()}
+
+	if err := c.storage.AddMember(ctx, cartID, m); err != nil {
+		if err != errNotFound && err != errOwnerMembership {
+			log.Printf("Failed to add the member: %d to the Cart: %d, error: %s", userID, cartID, err)
+		}
+		return Member{}, err
+	}
+
+	return m, nil
+}
+
+// RemoveMember from a Cart. Owners can remove any member, other members can only leave the Cart themselves.
+func (c *Carts) RemoveMember(ctx context.Context, cartID, userID int64) error {
+	if callerID, ok := caller(ctx); !ok || callerID != userID {
+		if err := c.authorize(ctx, cartID, RoleOwner); err != nil {
+			return err
+		}
+	}
+
+	if err := c.storage.RemoveMember(ctx, cartID, userID); err != nil {
+		if err != errMemberNotFound && err != errOwnerMembership {
+			log.Printf("Failed to remove the member: %d from the Cart: %d, error: %s", userID, cartID, err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Members of a Cart, the owner is the first of them. Only members can see each other.
+func (c *Carts) Members(ctx context.Context, cartID int64) ([]Member, error) {
+	if err := c.authorize(ctx, cartID, RoleViewer); err != nil {
+		return nil, err
+	}
+
+	members, err := c.storage.Members(ctx, cartID)
+	if err != nil {
+		if err != errNotFound {
+			log.Printf("Failed to get members of the Cart: %d, error: %s", cartID, err)
+		}
+		return nil, err
+	}
+
+	return members, nil
+}
@@ -0,0 +1,302 @@
+package cart
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRoleAllows(t *testing.T) {
+	cases := []struct {
+		role     Role
+		need     Role
+		expected bool
+	}{
+		{role: RoleOwner, need: RoleOwner, expected: true},
+		{role: RoleOwner, need: RoleViewer, expected: true},
+		{role: RoleEditor, need: RoleEditor, expected: true},
+		{role: RoleEditor, need: RoleOwner, expected: false},
+		{role: RoleViewer, need: RoleEditor, expected: false},
+		{role: "", need: RoleViewer, expected: false},
+		{role: "admin", need: RoleViewer, expected: false},
+	}
+
+	for _, c := range cases {
+		if got := c.role.allows(c.need); got != c.expected {
+			t.Errorf("Got %q allows %q: %t, expected: %t", c.role, c.need, got, c.expected)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	cases := []struct {
+		name          string
+		ctx           context.Context
+		role          Role
+		expectedError error
+	}{
+		{
+			name:          "No caller",
+			ctx:           context.Background(),
+			expectedError: errUnauthenticated,
+		},
+		{
+			name: "Trusted service",
+			ctx:  WithTrustedService(context.Background()),
+		},
+		{
+			name: "Trusted service with caller",
+			ctx:  WithCaller(WithTrustedService(context.Background()), 2),
+			role: RoleEditor,
+		},
+		{
+			name:          "Trusted service with viewer caller",
+			ctx:           WithCaller(WithTrustedService(context.Background()), 2),
+			role:          RoleViewer,
+			expectedError: errPermissionDenied,
+		},
+		{
+			name: "Editor",
+			ctx:  WithCaller(context.Background(), 2),
+			role: RoleEditor,
+		},
+		{
+			name:          "Viewer",
+			ctx:           WithCaller(context.Background(), 2),
+			role:          RoleViewer,
+			expectedError: errPermissionDenied,
+		},
+		{
+			name:          "Not a member",
+			ctx:           WithCaller(context.Background(), 2),
+			expectedError: errPermissionDenied,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var added bool
+
+			storage := &StorageMock{
+				MemberRoleFunc: func(ctx context.Context, cartID, userID int64) (Role, error) {
+					if userID != 2 {
+						t.Errorf("Got User: %d, expected: 2", userID)
+					}
+					return c.role, nil
+				},
+				AddProductFunc: func(ctx context.Context, cartID, productID int64, options Options, quantity uint32, note string, limit uint32) error {
+					added = true
+					return nil
+				},
+			}
+
+			err := New(storage).AddProduct(c.ctx, 1, 1, nil, 1, "")
+			if err != c.expectedError {
+				t.Fatalf("Got error: %v, expected: %v", err, c.expectedError)
+			}
+
+			if added != (c.expectedError == nil) {
+				t.Errorf("Got added: %t, expected: %t", added, c.expectedError == nil)
+			}
+		})
+	}
+}
+
+func TestDeleteNeedsOwner(t *testing.T) {
+	storage := &StorageMock{
+		MemberRoleFunc: func(ctx context.Context, cartID, userID int64) (Role, error) {
+			return RoleEditor, nil
+		},
+		DeleteCartFunc: func(ctx context.Context, cartID int64) error {
+			t.Error("Cart deleted by an editor")
+			return nil
+		},
+	}
+
+	if err := New(storage).Delete(WithCaller(context.Background(), 2), 1); err != errPermissionDenied {
+		t.Fatalf("Got error: %v, expected: %v", err, errPermissionDenied)
+	}
+}
+
+func TestInviteMember(t *testing.T) {
+	cases := []struct {
+		name          string
+		role          Role
+		callerRole    Role
+		expectedError error
+	}{
+		{
+			name:       "Invited by owner",
+			role:       RoleEditor,
+			callerRole: RoleOwner,
+		},
+		{
+			name:          "Invited by editor",
+			role:          RoleViewer,
+			callerRole:    RoleEditor,
+			expectedError: errPermissionDenied,
+		},
+		{
+			name:          "Unknown role",
+			role:          "admin",
+			callerRole:    RoleOwner,
+			expectedError: errUnknownRole,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var added Member
+
+			storage := &StorageMock{
+				MemberRoleFunc: func(ctx context.Context, cartID, userID int64) (Role, error) {
+					return c.callerRole, nil
+				},
+				AddMemberFunc: func(ctx context.Context, cartID int64, m Member) error {
+					added = m
+					return nil
+				},
+			}
+
+			m, err := New(storage).InviteMember(WithCaller(context.Background(), 1), 1, 2, c.role)
+			if err != c.expectedError {
+				t.Fatalf("Got error: %v, expected: %v", err, c.expectedError)
+			}
+			if err != nil {
+				if added.UserID != 0 {
+					t.Errorf("Got added member: %d, expected none", added.UserID)
+				}
+				return
+			}
+
+			if m.UserID != 2 || m.Role != c.role || added != m {
+				t.Errorf("Got member: %+v, added: %+v, expected User: 2 with role: %q", m, added, c.role)
+			}
+		})
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	cases := []struct {
+		name          string
+		callerID      int64
+		userID        int64
+		callerRole    Role
+		expectedError error
+	}{
+		{
+			name:       "Member leaves",
+			callerID:   2,
+			userID:     2,
+			callerRole: RoleViewer,
+		},
+		{
+			name:       "Removed by owner",
+			callerID:   1,
+			userID:     2,
+			callerRole: RoleOwner,
+		},
+		{
+			name:          "Removed by editor",
+			callerID:      3,
+			userID:        2,
+			callerRole:    RoleEditor,
+			expectedError: errPermissionDenied,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var removed bool
+
+			storage := &StorageMock{
+				MemberRoleFunc: func(ctx context.Context, cartID, userID int64) (Role, error) {
+					return c.callerRole, nil
+				},
+				RemoveMemberFunc: func(ctx context.Context, cartID, userID int64) error {
+					removed = true
+					return nil
+				},
+			}
+
+			err := New(storage).RemoveMember(WithCaller(context.Background(), c.callerID), 1, c.userID)
+			if err != c.expectedError {
+				t.Fatalf("Got error: %v, expected: %v", err, c.expectedError)
+			}
+
+			if removed != (c.expectedError == nil) {
+				t.Errorf("Got removed: %t, expected: %t", removed, c.expectedError == nil)
+			}
+		})
+	}
+}
+
+func TestReadsNeedMember(t *testing.T) {
+	storage := &StorageMock{
+		MemberRoleFunc: func(ctx context.Context, cartID, userID int64) (Role, error) {
+			return "", nil
+		},
+		CartByIDFunc: func(ctx context.Context, id int64) (Cart, error) {
+			t.Errorf("Cart: %d read by a non-member", id)
+			return Cart{ID: id, UserID: 1}, nil
+		},
+		CartsByUserFunc: func(ctx context.Context, userID, beforeID int64, limit int) ([]Cart, error) {
+			t.Errorf("Carts of User: %d listed by another User", userID)
+			return nil, nil
+		},
+		ActiveCartFunc: func(ctx context.Context, userID int64) (Cart, error) {
+			t.Errorf("Active Cart of User: %d read by another User", userID)
+			return Cart{UserID: userID}, nil
+		},
+		HistoryFunc: func(ctx context.Context, cartID, beforeID int64, limit int) ([]HistoryEntry, error) {
+			t.Errorf("History of Cart: %d read by a non-member", cartID)
+			return nil, nil
+		},
+		SnapshotFunc: func(ctx context.Context, id int64) (Snapshot, error) {
+			return Snapshot{ID: id, Cart: Cart{ID: 1, UserID: 1}}, nil
+		},
+		CreateSnapshotFunc: func(ctx context.Context, snapshot Snapshot) (Snapshot, error) {
+			t.Errorf("Cart: %d snapshotted by a non-member", snapshot.Cart.ID)
+			return snapshot, nil
+		},
+	}
+
+	carts := New(storage)
+
+	reads := map[string]func(ctx context.Context) error{
+		"Cart": func(ctx context.Context) error {
+			_, err := carts.Cart(ctx, 1)
+			return err
+		},
+		"ListByUser": func(ctx context.Context) error {
+			_, _, err := carts.ListByUser(ctx, 1, Page{})
+			return err
+		},
+		"ActiveCart": func(ctx context.Context) error {
+			_, err := carts.ActiveCart(ctx, 1)
+			return err
+		},
+		"History": func(ctx context.Context) error {
+			_, _, err := carts.History(ctx, 1, Page{})
+			return err
+		},
+		"Snapshot": func(ctx context.Context) error {
+			_, err := carts.Snapshot(ctx, 1)
+			return err
+		},
+		"GetSnapshot": func(ctx context.Context) error {
+			_, err := carts.GetSnapshot(ctx, 5)
+			return err
+		},
+	}
+
+	for name, read := range reads {
+		t.Run(name, func(t *testing.T) {
+			if err := read(WithCaller(context.Background(), 2)); err != errPermissionDenied {
+				t.Errorf("Got error for a non-member: %v, expected: %v", err, errPermissionDenied)
+			}
+			if err := read(context.Background()); err != errUnauthenticated {
+				t.Errorf("Got error without a caller: %v, expected: %v", err, errUnauthenticated)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package cart
+
+import "errors"
+
+var (
+	errSameCart             = errors.New("source and target carts are the same")
+	errUnknownMergeStrategy = errors.New("unknown merge strategy")
+)
+
+// MergeStrategy defines how quantities are combined when both Carts contain the same Product.
+type MergeStrategy int
+
+const (
+	// MergeSum adds source quantity to the target one.
+	MergeSum MergeStrategy = iota
+	// MergeMax keeps the largest of two quantities.
+	MergeMax
+	// MergePreferSource replaces target quantity with the source one.
+	MergePreferSource
+)
+
+func (s MergeStrategy) valid() bool {
+	return s >= MergeSum && s <= MergePreferSource
+}
+
+// mergeQuantity returns quantity of a Product present in both Carts.
+func mergeQuantity(s MergeStrategy, target, source uint32) (uint32, error) {
+	switch s {
+	case MergeSum:
+		if uint64(target)+uint64(source) > maxQuantity {
+			return 0, errQuantityTooLarge
+		}
+		return target + source, nil
+	case MergeMax:
+		if source > target {
+			return source, nil
+		}
+		return target, nil
+	case MergePreferSource:
+		return source, nil
+	}
+
+	return 0, errUnknownMergeStrategy
+}
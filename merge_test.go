@@ -0,0 +1,137 @@
+package cart
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMergeQuantity(t *testing.T) {
+	cases := []struct {
+		name          string
+		strategy      MergeStrategy
+		target        uint32
+		source        uint32
+		expected      uint32
+		expectedError error
+	}{
+		{
+			name:     "Sum",
+			strategy: MergeSum,
+			target:   2,
+			source:   3,
+			expected: 5,
+		},
+		{
+			name:          "Sum overflow",
+			strategy:      MergeSum,
+			target:        maxQuantity,
+			source:        1,
+			expectedError: errQuantityTooLarge,
+		},
+		{
+			name:     "Max of target",
+			strategy: MergeMax,
+			target:   7,
+			source:   3,
+			expected: 7,
+		},
+		{
+			name:     "Max of source",
+			strategy: MergeMax,
+			target:   1,
+			source:   3,
+			expected: 3,
+		},
+		{
+			name:     "Prefer source",
+			strategy: MergePreferSource,
+			target:   7,
+			source:   3,
+			expected: 3,
+		},
+		{
+			name:          "Unknown strategy",
+			strategy:      MergeStrategy(42),
+			target:        1,
+			source:        1,
+			expectedError: errUnknownMergeStrategy,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := mergeQuantity(c.strategy, c.target, c.source)
+			if err != c.expectedError {
+				t.Fatalf("Got error: %v, expected: %v", err, c.expectedError)
+			}
+
+			if actual != c.expected {
+				t.Errorf("Got quantity: %d, expected: %d", actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestMergeNeedsSourceOwner(t *testing.T) {
+	const (
+		guestCartID int64 = 1
+		userCartID  int64 = 2
+		guestID     int64 = -5
+		userID      int64 = 7
+	)
+
+	roles := map[int64]map[int64]Role{
+		guestCartID: {guestID: RoleOwner},
+		userCartID:  {userID: RoleOwner, guestID: RoleEditor},
+	}
+
+	cases := []struct {
+		name          string
+		ctx           context.Context
+		expectedError error
+	}{
+		{
+			name:          "Guest Cart of another User",
+			ctx:           WithCaller(context.Background(), userID),
+			expectedError: errPermissionDenied,
+		},
+		{
+			name: "Own guest Cart",
+			ctx:  WithCaller(context.Background(), guestID),
+		},
+		{
+			name: "Trusted service",
+			ctx:  WithTrustedService(context.Background()),
+		},
+		{
+			name:          "No caller",
+			ctx:           context.Background(),
+			expectedError: errUnauthenticated,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var merged bool
+
+			storage := &StorageMock{
+				MemberRoleFunc: func(ctx context.Context, cartID, userID int64) (Role, error) {
+					return roles[cartID][userID], nil
+				},
+				MergeCartsFunc: func(ctx context.Context, sourceID, targetID int64, strategy MergeStrategy) (Cart, error) {
+					merged = true
+					return Cart{ID: targetID}, nil
+				},
+			}
+
+			_, err := New(storage).Merge(c.ctx, guestCartID, userCartID, MergeSum)
+			if err != c.expectedError {
+				t.Fatalf("Got error: %v, expected: %v", err, c.expectedError)
+			}
+
+			if merged != (c.expectedError == nil) {
+				t.Errorf("Got merged: %t, expected: %t", merged, c.expectedError == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX carts_user_id_idx ON carts (user_id, updated_at);
+
+-- +goose Down
+DROP INDEX carts_user_id_idx;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE carts ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'open';
+
+-- +goose Down
+ALTER TABLE carts DROP COLUMN status;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE carts ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE carts DROP COLUMN version;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE idempotency_keys (
+  idempotency_key	VARCHAR(255)	NOT NULL,
+  method		VARCHAR(64)	NOT NULL,
+  response		BYTEA,
+  created_at		TIMESTAMP	NOT NULL,
+  expires_at		TIMESTAMP	NOT NULL,
+  PRIMARY KEY (idempotency_key, method)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+
+-- +goose Down
+DROP TABLE idempotency_keys;
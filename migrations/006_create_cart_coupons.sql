@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE cart_coupons (
+  cart_id	INTEGER		NOT NULL REFERENCES carts,
+  code		VARCHAR(64)	NOT NULL,
+  created_at	TIMESTAMP	NOT NULL,
+  PRIMARY KEY (cart_id, code)
+);
+
+-- +goose Down
+DROP TABLE cart_coupons;
@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE line_items ADD COLUMN reservation_id VARCHAR(64);
+ALTER TABLE line_items ADD COLUMN reserved_until TIMESTAMP;
+
+CREATE INDEX line_items_reserved_until_idx ON line_items (reserved_until) WHERE reserved_until IS NOT NULL;
+
+-- +goose Down
+DROP INDEX line_items_reserved_until_idx;
+ALTER TABLE line_items DROP COLUMN reserved_until;
+ALTER TABLE line_items DROP COLUMN reservation_id;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE outbox (
+  event_id	BIGSERIAL	PRIMARY KEY,
+  event_type	VARCHAR(64)	NOT NULL,
+  cart_id	INTEGER		NOT NULL,
+  payload	JSONB		NOT NULL,
+  created_at	TIMESTAMP	NOT NULL,
+  published_at	TIMESTAMP
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (event_id) WHERE published_at IS NULL;
+
+-- +goose Down
+DROP TABLE outbox;
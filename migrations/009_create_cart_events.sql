@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE cart_events (
+  event_id	BIGSERIAL	PRIMARY KEY,
+  cart_id	INTEGER		NOT NULL,
+  actor		VARCHAR(255)	NOT NULL,
+  operation	VARCHAR(64)	NOT NULL,
+  product_id	INTEGER		NOT NULL,
+  old_quantity	INTEGER		NOT NULL,
+  new_quantity	INTEGER		NOT NULL,
+  detail	VARCHAR(255)	NOT NULL,
+  created_at	TIMESTAMP	NOT NULL
+);
+
+CREATE INDEX cart_events_cart_id_idx ON cart_events (cart_id, event_id);
+
+-- +goose Down
+DROP TABLE cart_events;
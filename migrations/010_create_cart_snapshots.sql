@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE cart_snapshots (
+  snapshot_id	BIGSERIAL	PRIMARY KEY,
+  cart_id	INTEGER		NOT NULL,
+  cart_version	BIGINT		NOT NULL,
+  content	JSONB		NOT NULL,
+  hash		CHAR(64)	NOT NULL,
+  created_at	TIMESTAMP	NOT NULL
+);
+
+CREATE INDEX cart_snapshots_cart_id_idx ON cart_snapshots (cart_id);
+
+-- +goose Down
+DROP TABLE cart_snapshots;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE line_items ADD COLUMN saved BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE line_items DROP COLUMN saved;
@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE line_items ADD COLUMN options JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE line_items ADD COLUMN options_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE line_items ADD COLUMN note VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX line_items_cart_id_product_id_idx ON line_items (cart_id, product_id, options_key);
+
+-- +goose Down
+DROP INDEX line_items_cart_id_product_id_idx;
+ALTER TABLE line_items DROP COLUMN note;
+ALTER TABLE line_items DROP COLUMN options_key;
+ALTER TABLE line_items DROP COLUMN options;
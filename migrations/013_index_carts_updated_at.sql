@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX carts_updated_at_idx ON carts (updated_at);
+
+-- +goose Down
+DROP INDEX carts_updated_at_idx;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE carts ADD COLUMN abandoned_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE carts DROP COLUMN abandoned_at;
@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE carts ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX carts_deleted_at_idx ON carts (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX carts_deleted_at_idx;
+ALTER TABLE carts DROP COLUMN deleted_at;
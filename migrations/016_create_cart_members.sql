@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE cart_members (
+  cart_id	INTEGER		NOT NULL REFERENCES carts,
+  user_id	INTEGER		NOT NULL,
+  role		VARCHAR(16)	NOT NULL,
+  created_at	TIMESTAMP	NOT NULL,
+  PRIMARY KEY (cart_id, user_id)
+);
+
+CREATE INDEX cart_members_user_id_idx ON cart_members (user_id);
+
+-- +goose Down
+DROP TABLE cart_members;
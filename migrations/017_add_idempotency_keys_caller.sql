@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE idempotency_keys ADD COLUMN caller VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (idempotency_key, method, caller);
+
+-- +goose Down
+DELETE FROM idempotency_keys WHERE caller <> '';
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (idempotency_key, method);
+ALTER TABLE idempotency_keys DROP COLUMN caller;
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX outbox_published_at_idx ON outbox (published_at) WHERE published_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX outbox_published_at_idx;
@@ -0,0 +1,99 @@
+package cart
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxOptions of a single LineItem.
+	maxOptions = 16
+	// maxOptionLength limits length of option keys and values in characters.
+	maxOptionLength = 64
+	// maxNoteLength limits length of LineItem notes in characters, line_items.note column is VARCHAR(255).
+	maxNoteLength = 255
+)
+
+var (
+	errInvalidOptions = errors.New("invalid line item options")
+	errNoteTooLong    = errors.New("line item note is too long")
+)
+
+// Options of a LineItem, e.g. size and color of a T-shirt. A Cart has a separate LineItem for every
+// combination of Product and normalized Options.
+type Options map[string]string
+
+// normalize returns Options with trimmed lower case keys and trimmed values. Options with empty values are dropped.
+// Normalized Options are nil when there are none.
+func (o Options) normalize() (Options, error) {
+	var normalized Options
+
+	for k, v := range o {
+		k, v = strings.ToLower(strings.TrimSpace(k)), strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		if k == "" || utf8.RuneCountInString(k) > maxOptionLength || utf8.RuneCountInString(v) > maxOptionLength {
+			return nil, errInvalidOptions
+		}
+
+		if normalized == nil {
+			normalized = make(Options, len(o))
+		}
+		normalized[k] = v
+	}
+
+	if len(normalized) > maxOptions {
+		return nil, errInvalidOptions
+	}
+
+	return normalized, nil
+}
+
+// key is a canonical representation of normalized Options, it is empty when there are no Options.
+func (o Options) key() string {
+	values := make(url.Values, len(o))
+	for k, v := range o {
+		values.Set(k, v)
+	}
+
+	return values.Encode()
+}
+
+// decodeOptions stored as JSON.
+func decodeOptions(b []byte) (Options, error) {
+	var o Options
+	if err := json.Unmarshal(b, &o); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal LineItem options: %s", err)
+	}
+
+	if len(o) == 0 {
+		return nil, nil
+	}
+
+	return o, nil
+}
+
+// encodeOptions as JSON.
+func encodeOptions(o Options) ([]byte, error) {
+	if o == nil {
+		o = Options{}
+	}
+
+	return json.Marshal(o)
+}
+
+// validNote checks length of a LineItem note and trims it.
+func validNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxNoteLength {
+		return "", errNoteTooLong
+	}
+
+	return note, nil
+}
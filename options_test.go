@@ -0,0 +1,73 @@
+package cart
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNormalizeOptions(t *testing.T) {
+	cases := []struct {
+		name          string
+		input         Options
+		expected      Options
+		expectedKey   string
+		expectedError error
+	}{
+		{
+			name: "No options",
+		},
+		{
+			name:        "Keys are case insensitive",
+			input:       Options{" Size ": "M ", "COLOR": "red", "engraving": " "},
+			expected:    Options{"size": "M", "color": "red"},
+			expectedKey: "color=red&size=M",
+		},
+		{
+			name:          "Empty key",
+			input:         Options{" ": "red"},
+			expectedError: errInvalidOptions,
+		},
+		{
+			name:          "Long value",
+			input:         Options{"engraving": strings.Repeat("a", maxOptionLength+1)},
+			expectedError: errInvalidOptions,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := c.input.normalize()
+			if err != c.expectedError {
+				t.Fatalf("Got error: %v, expected: %v", err, c.expectedError)
+			}
+
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("Options mismatch (-expected +got):\n%s", diff)
+			}
+
+			if key := actual.key(); key != c.expectedKey {
+				t.Errorf("Got key: %q, expected: %q", key, c.expectedKey)
+			}
+		})
+	}
+}
+
+func TestOptionsRoundTrip(t *testing.T) {
+	for _, o := range []Options{nil, {"size": "M"}} {
+		b, err := encodeOptions(o)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		decoded, err := decodeOptions(b)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if diff := cmp.Diff(o, decoded); diff != "" {
+			t.Errorf("Options mismatch (-expected +got):\n%s", diff)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package cart
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var errInvalidPageToken = errors.New("invalid page token")
+
+// Page selects a part of a list. Empty Token selects the first page.
+type Page struct {
+	Token string
+	Size  int
+}
+
+// size returns the number of elements on the Page within allowed limits.
+func (p Page) size() int {
+	switch {
+	case p.Size <= 0:
+		return defaultPageSize
+	case p.Size > maxPageSize:
+		return maxPageSize
+	}
+
+	return p.Size
+}
+
+// encodePageToken builds an opaque token pointing to the element after ID.
+func encodePageToken(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+// decodePageToken returns ID stored in the token, zero for an empty token.
+func decodePageToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errInvalidPageToken
+	}
+
+	id, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errInvalidPageToken
+	}
+
+	return id, nil
+}
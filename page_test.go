@@ -0,0 +1,80 @@
+package cart
+
+import "testing"
+
+func TestPageToken(t *testing.T) {
+	var id int64 = 100500
+
+	actual, err := decodePageToken(encodePageToken(id))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if actual != id {
+		t.Errorf("Got ID: %d, expected: %d", actual, id)
+	}
+}
+
+func TestDecodePageToken(t *testing.T) {
+	cases := []struct {
+		name          string
+		token         string
+		expected      int64
+		expectedError error
+	}{
+		{
+			name:     "Empty token",
+			token:    "",
+			expected: 0,
+		},
+		{
+			name:          "Not base64",
+			token:         "???",
+			expectedError: errInvalidPageToken,
+		},
+		{
+			name:          "Not a number",
+			token:         "YWJj",
+			expectedError: errInvalidPageToken,
+		},
+		{
+			name:          "Negative ID",
+			token:         encodePageToken(-1),
+			expectedError: errInvalidPageToken,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := decodePageToken(c.token)
+			if err != c.expectedError {
+				t.Fatalf("Got error: %v, expected: %v", err, c.expectedError)
+			}
+
+			if actual != c.expected {
+				t.Errorf("Got ID: %d, expected: %d", actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	cases := []struct {
+		name     string
+		size     int
+		expected int
+	}{
+		{name: "Default", size: 0, expected: defaultPageSize},
+		{name: "Negative", size: -1, expected: defaultPageSize},
+		{name: "Requested", size: 5, expected: 5},
+		{name: "Too large", size: maxPageSize + 1, expected: maxPageSize},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := (Page{Size: c.size}).size(); actual != c.expected {
+				t.Errorf("Got size: %d, expected: %d", actual, c.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	errPriceNotFound    = errors.New("product price not found")
+	errCurrencyMismatch = errors.New("cart products are priced in different currencies")
+)
+
+// Price of a single Product unit. Amount is in minor currency units, e.g. cents.
+type Price struct {
+	Amount   int64
+	Currency string
+}
+
+// PriceProvider returns current prices of Products. Billing is the source of prices in production.
+type PriceProvider interface {
+	// Prices returns prices of the requested Products. Products without a price are omitted.
+	Prices(ctx context.Context, productIDs []int64) (map[int64]Price, error)
+}
+
+// StaticPrices is an in-memory PriceProvider for tests and local runs.
+type StaticPrices map[int64]Price
+
+// Prices returns prices of the requested Products known to sp.
+func (sp StaticPrices) Prices(ctx context.Context, productIDs []int64) (map[int64]Price, error) {
+	result := make(map[int64]Price, len(productIDs))
+
+	for _, id := range productIDs {
+		if p, ok := sp[id]; ok {
+			result[id] = p
+		}
+	}
+
+	return result, nil
+}
+
+// Option configures Carts.
+type Option func(*Carts)
+
+// WithPrices sets PriceProvider used to price Carts. Without it Carts are returned without prices.
+func WithPrices(p PriceProvider) Option {
+	return func(c *Carts) {
+		c.prices = p
+	}
+}
+
+// price fills in unit prices and line totals of the LineItems, Subtotal and Currency of the Cart.
+// All Products of the Cart must have a price in the same currency. LineItems that can not be priced are left
+// without price and not counted in the Subtotal, the error is returned once the rest of the Cart is priced.
+func (c *Carts) price(ctx context.Context, cart *Cart) error {
+	if c.prices == nil || len(cart.Items) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(cart.Items))
+	for _, li := range cart.Items {
+		ids = append(ids, li.ProductID)
+	}
+
+	prices, err := c.prices.Prices(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get prices: %s", err)
+	}
+
+	var (
+		subtotal int64
+		currency string
+		perr     error
+	)
+
+	for i, li := range cart.Items {
+		p, ok := prices[li.ProductID]
+		if !ok {
+			perr = errPriceNotFound
+			continue
+		}
+
+		if currency == "" {
+			currency = p.Currency
+		}
+		if p.Currency != currency {
+			perr = errCurrencyMismatch
+			continue
+		}
+
+		cart.Items[i].UnitPrice = p.Amount
+		cart.Items[i].LineTotal = p.Amount * int64(li.Quantity)
+		subtotal += cart.Items[i].LineTotal
+	}
+
+	cart.Subtotal = subtotal
+	cart.Currency = currency
+
+	return perr
+}
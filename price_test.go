@@ -0,0 +1,116 @@
+package cart
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPrice(t *testing.T) {
+	prices := StaticPrices{
+		1: {Amount: 250, Currency: "USD"},
+		2: {Amount: 1000, Currency: "USD"},
+		3: {Amount: 900, Currency: "EUR"},
+	}
+
+	cases := []struct {
+		name          string
+		prices        PriceProvider
+		input         Cart
+		expected      Cart
+		expectedError error
+	}{
+		{
+			name:     "No provider",
+			input:    Cart{Items: []LineItem{{ProductID: 1, Quantity: 2}}},
+			expected: Cart{Items: []LineItem{{ProductID: 1, Quantity: 2}}},
+		},
+		{
+			name:     "Empty Cart",
+			prices:   prices,
+			input:    Cart{},
+			expected: Cart{},
+		},
+		{
+			name:   "Priced",
+			prices: prices,
+			input:  Cart{Items: []LineItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 1}}},
+			expected: Cart{
+				Items: []LineItem{
+					{ProductID: 1, Quantity: 2, UnitPrice: 250, LineTotal: 500},
+					{ProductID: 2, Quantity: 1, UnitPrice: 1000, LineTotal: 1000},
+				},
+				Subtotal: 1500,
+				Currency: "USD",
+			},
+		},
+		{
+			name:   "Missing price",
+			prices: prices,
+			input:  Cart{Items: []LineItem{{ProductID: 4, Quantity: 1}, {ProductID: 2, Quantity: 1}}},
+			expected: Cart{
+				Items: []LineItem{
+					{ProductID: 4, Quantity: 1},
+					{ProductID: 2, Quantity: 1, UnitPrice: 1000, LineTotal: 1000},
+				},
+				Subtotal: 1000,
+				Currency: "USD",
+			},
+			expectedError: errPriceNotFound,
+		},
+		{
+			name:   "Different currencies",
+			prices: prices,
+			input:  Cart{Items: []LineItem{{ProductID: 1, Quantity: 1}, {ProductID: 3, Quantity: 1}}},
+			expected: Cart{
+				Items: []LineItem{
+					{ProductID: 1, Quantity: 1, UnitPrice: 250, LineTotal: 250},
+					{ProductID: 3, Quantity: 1},
+				},
+				Subtotal: 250,
+				Currency: "USD",
+			},
+			expectedError: errCurrencyMismatch,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			carts := New(nil, WithPrices(c.prices))
+
+			cart := c.input
+			err := carts.price(context.Background(), &cart)
+			if err != c.expectedError {
+				t.Fatalf("Got error: %v, expected: %v", err, c.expectedError)
+			}
+
+			if diff := cmp.Diff(c.expected, cart); diff != "" {
+				t.Errorf("price() mismatch (+got -want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCartPartiallyPriced(t *testing.T) {
+	storage := &StorageMock{
+		CartByIDFunc: func(ctx context.Context, id int64) (Cart, error) {
+			return Cart{ID: id, Items: []LineItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 1}}}, nil
+		},
+	}
+
+	carts := New(storage, WithPrices(StaticPrices{1: {Amount: 250, Currency: "USD"}}))
+
+	cart, err := carts.Cart(WithTrustedService(context.Background()), 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if cart.Subtotal != 500 || cart.Items[1].UnitPrice != 0 {
+		t.Errorf("Got subtotal: %d, unpriced line: %+v, expected subtotal of priced lines only", cart.Subtotal, cart.Items[1])
+	}
+
+	if _, err := carts.Snapshot(WithTrustedService(context.Background()), 1); err != errPriceNotFound {
+		t.Errorf("Got Snapshot error: %v, expected: %v", err, errPriceNotFound)
+	}
+}
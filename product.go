@@ -0,0 +1,97 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cooldryplace/cart/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errProductNotFound = errors.New("product not found")
+	errProductInactive = errors.New("product is discontinued")
+)
+
+// Product details relevant to Carts. Zero MaxQuantity means there is no limit of the Product quantity in a Cart.
+type Product struct {
+	ID          int64
+	Active      bool
+	MaxQuantity uint32
+}
+
+// ProductCatalog returns Products that can be added to Carts. Products service is the source of Products in production.
+type ProductCatalog interface {
+	// Product returns Product with the ID or errProductNotFound.
+	Product(ctx context.Context, id int64) (Product, error)
+}
+
+// StaticCatalog is an in-memory ProductCatalog for tests and local runs.
+type StaticCatalog map[int64]Product
+
+// Product returns Product with the ID.
+func (sc StaticCatalog) Product(ctx context.Context, id int64) (Product, error) {
+	p, ok := sc[id]
+	if !ok {
+		return Product{}, errProductNotFound
+	}
+
+	return p, nil
+}
+
+// GRPCCatalog is a ProductCatalog backed by Products gRPC service.
+type GRPCCatalog struct {
+	client proto.ProductsClient
+}
+
+// NewGRPCCatalog returns ProductCatalog that uses Products service client.
+func NewGRPCCatalog(c proto.ProductsClient) *GRPCCatalog {
+	return &GRPCCatalog{client: c}
+}
+
+// Product returns Product with the ID.
+func (gc *GRPCCatalog) Product(ctx context.Context, id int64) (Product, error) {
+	resp, err := gc.client.GetProduct(ctx, &proto.ProductRequest{Id: id})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return Product{}, errProductNotFound
+		}
+		return Product{}, fmt.Errorf("failed to get the Product: %s", err)
+	}
+
+	return Product{
+		ID:          resp.Id,
+		Active:      resp.Active,
+		MaxQuantity: resp.MaxOrderQuantity,
+	}, nil
+}
+
+// WithCatalog sets ProductCatalog used to validate Products before they are added to Carts.
+// Without it any Product ID is accepted.
+func WithCatalog(pc ProductCatalog) Option {
+	return func(c *Carts) {
+		c.catalog = pc
+	}
+}
+
+// orderable returns the limit of the Product quantity in a Cart, zero means no limit.
+// It fails when the Product does not exist or is discontinued.
+func (c *Carts) orderable(ctx context.Context, productID int64) (uint32, error) {
+	if c.catalog == nil {
+		return 0, nil
+	}
+
+	p, err := c.catalog.Product(ctx, productID)
+	if err != nil {
+		return 0, err
+	}
+
+	if !p.Active {
+		return 0, errProductInactive
+	}
+
+	return p.MaxQuantity, nil
+}
@@ -0,0 +1,102 @@
+package cart
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cooldryplace/cart/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAddProductValidation(t *testing.T) {
+	catalog := StaticCatalog{
+		1: {ID: 1, Active: true},
+		2: {ID: 2, Active: true, MaxQuantity: 3},
+		3: {ID: 3},
+	}
+
+	cases := []struct {
+		name          string
+		productID     int64
+		expectedLimit uint32
+		expectedError error
+	}{
+		{
+			name:      "No limit",
+			productID: 1,
+		},
+		{
+			name:          "Limited quantity",
+			productID:     2,
+			expectedLimit: 3,
+		},
+		{
+			name:          "Discontinued",
+			productID:     3,
+			expectedError: errProductInactive,
+		},
+		{
+			name:          "Unknown",
+			productID:     4,
+			expectedError: errProductNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var stored bool
+
+			storage := &StorageMock{
+				AddProductFunc: func(ctx context.Context, cartID, productID int64, options Options, quantity uint32, note string, limit uint32) error {
+					stored = true
+					if limit != c.expectedLimit {
+						t.Errorf("Got limit: %d, expected: %d", limit, c.expectedLimit)
+					}
+					return nil
+				},
+			}
+
+			carts := New(storage, WithCatalog(catalog))
+
+			err := carts.AddProduct(WithTrustedService(context.Background()), 1, c.productID, nil, 1, "")
+			if err != c.expectedError {
+				t.Fatalf("Got error: %v, expected: %v", err, c.expectedError)
+			}
+
+			if stored != (err == nil) {
+				t.Errorf("Got Product stored: %t, expected: %t", stored, err == nil)
+			}
+		})
+	}
+}
+
+type productsClientMock struct {
+	resp *proto.Product
+	err  error
+}
+
+func (m productsClientMock) GetProduct(ctx context.Context, req *proto.ProductRequest, opts ...grpc.CallOption) (*proto.Product, error) {
+	return m.resp, m.err
+}
+
+func TestGRPCCatalog(t *testing.T) {
+	catalog := NewGRPCCatalog(productsClientMock{err: status.Error(codes.NotFound, "no such product")})
+
+	if _, err := catalog.Product(context.Background(), 1); err != errProductNotFound {
+		t.Errorf("Got error: %v, expected: %s", err, errProductNotFound)
+	}
+
+	catalog = NewGRPCCatalog(productsClientMock{resp: &proto.Product{Id: 1, Active: true, MaxOrderQuantity: 5}})
+
+	p, err := catalog.Product(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if expected := (Product{ID: 1, Active: true, MaxQuantity: 5}); p != expected {
+		t.Errorf("Got Product: %+v, expected: %+v", p, expected)
+	}
+}
@@ -0,0 +1,320 @@
+package cart
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+var (
+	errInvalidCouponCode = errors.New("invalid coupon code")
+	errCouponNotFound    = errors.New("coupon not found")
+	errCouponNotApplied  = errors.New("coupon is not applied to the cart")
+)
+
+// Discount is a part of the Cart price taken off by a coupon.
+// ProductID is zero for discounts applied to the whole Cart.
+type Discount struct {
+	Code        string
+	ProductID   int64
+	Description string
+	Amount      int64
+}
+
+// Rule of a promotion. Apply returns discounts for a priced Cart, Code of the discounts is set by Carts.
+type Rule interface {
+	Apply(cart Cart) []Discount
+}
+
+// PercentOff takes Percent off the price of the Product in all its LineItems, or off the Cart subtotal when ProductID is zero.
+type PercentOff struct {
+	Percent   int64
+	ProductID int64
+}
+
+// Apply PercentOff to the Cart.
+func (r PercentOff) Apply(cart Cart) []Discount {
+	if r.ProductID == 0 {
+		return []Discount{{
+			Description: fmt.Sprintf("%d%% off", r.Percent),
+			Amount:      cart.Subtotal * r.Percent / 100,
+		}}
+	}
+
+	for _, li := range byProduct(cart.Items) {
+		if li.ProductID == r.ProductID {
+			return []Discount{{
+				ProductID:   li.ProductID,
+				Description: fmt.Sprintf("%d%% off the product", r.Percent),
+				Amount:      li.LineTotal * r.Percent / 100,
+			}}
+		}
+	}
+
+	return nil
+}
+
+// FixedOff takes Amount off the Cart subtotal.
+type FixedOff struct {
+	Amount int64
+}
+
+// Apply FixedOff to the Cart.
+func (r FixedOff) Apply(cart Cart) []Discount {
+	return []Discount{{
+		Description: fmt.Sprintf("%d off", r.Amount),
+		Amount:      r.Amount,
+	}}
+}
+
+// BuyXGetY makes Get units of the Product free for every Buy units paid. Units of all LineItems of the Product count.
+type BuyXGetY struct {
+	ProductID int64
+	Buy       uint32
+	Get       uint32
+}
+
+// Apply BuyXGetY to the Cart.
+func (r BuyXGetY) Apply(cart Cart) []Discount {
+	if r.Get == 0 {
+		return nil
+	}
+
+	for _, li := range byProduct(cart.Items) {
+		if li.ProductID == r.ProductID {
+			free := li.Quantity / (r.Buy + r.Get) * r.Get
+
+			return []Discount{{
+				ProductID:   li.ProductID,
+				Description: fmt.Sprintf("buy %d get %d free", r.Buy, r.Get),
+				Amount:      li.UnitPrice * int64(free),
+			}}
+		}
+	}
+
+	return nil
+}
+
+// MinSubtotal applies the Rule only to Carts with subtotal of at least Amount.
+type MinSubtotal struct {
+	Amount int64
+	Rule   Rule
+}
+
+// Apply MinSubtotal to the Cart.
+func (r MinSubtotal) Apply(cart Cart) []Discount {
+	if cart.Subtotal < r.Amount {
+		return nil
+	}
+
+	return r.Rule.Apply(cart)
+}
+
+// Types of promotion Rules in JSON.
+const (
+	rulePercentOff  = "percent_off"
+	ruleFixedOff    = "fixed_off"
+	ruleBuyXGetY    = "buy_x_get_y"
+	ruleMinSubtotal = "min_subtotal"
+)
+
+// ruleConfig is a promotion Rule in JSON, Type selects the Rule and the fields it uses,
+// e.g. {"type": "buy_x_get_y", "productId": 100, "buy": 2, "get": 1}. MinSubtotal wraps another Rule.
+type ruleConfig struct {
+	Type      string      `json:"type"`
+	Percent   int64       `json:"percent"`
+	ProductID int64       `json:"productId"`
+	Amount    int64       `json:"amount"`
+	Buy       uint32      `json:"buy"`
+	Get       uint32      `json:"get"`
+	Rule      *ruleConfig `json:"rule"`
+}
+
+// rule returns the configured Rule.
+func (rc ruleConfig) rule() (Rule, error) {
+	switch rc.Type {
+	case rulePercentOff:
+		if rc.Percent <= 0 || rc.Percent > 100 {
+			return nil, fmt.Errorf("percent must be between 1 and 100, got: %d", rc.Percent)
+		}
+		return PercentOff{Percent: rc.Percent, ProductID: rc.ProductID}, nil
+	case ruleFixedOff:
+		if rc.Amount <= 0 {
+			return nil, fmt.Errorf("amount must be positive, got: %d", rc.Amount)
+		}
+		return FixedOff{Amount: rc.Amount}, nil
+	case ruleBuyXGetY:
+		if rc.ProductID == 0 {
+			return nil, errors.New("buy_x_get_y needs a productId")
+		}
+		if rc.Buy == 0 || rc.Get == 0 {
+			return nil, fmt.Errorf("buy and get must be positive, got: %d and %d", rc.Buy, rc.Get)
+		}
+		return BuyXGetY{ProductID: rc.ProductID, Buy: rc.Buy, Get: rc.Get}, nil
+	case ruleMinSubtotal:
+		if rc.Amount <= 0 {
+			return nil, fmt.Errorf("amount must be positive, got: %d", rc.Amount)
+		}
+		if rc.Rule == nil {
+			return nil, errors.New("min_subtotal needs a rule to apply")
+		}
+		r, err := rc.Rule.rule()
+		if err != nil {
+			return nil, err
+		}
+		return MinSubtotal{Amount: rc.Amount, Rule: r}, nil
+	}
+
+	return nil, fmt.Errorf("unknown rule type: %q", rc.Type)
+}
+
+// Coupon is a discount code with its promotion Rule.
+type Coupon struct {
+	Code string
+	Rule Rule
+}
+
+// CouponProvider returns coupons by codes. It returns errCouponNotFound for unknown and expired codes.
+type CouponProvider interface {
+	Coupon(ctx context.Context, code string) (Coupon, error)
+}
+
+// StaticCoupons is an in-memory CouponProvider keyed by normalized coupon codes.
+type StaticCoupons map[string]Coupon
+
+// UnmarshalJSON decodes coupons from an object of codes and their Rules,
+// e.g. {"SPRING10": {"type": "percent_off", "percent": 10}}.
+func (sc *StaticCoupons) UnmarshalJSON(b []byte) error {
+	var configs map[string]ruleConfig
+	if err := json.Unmarshal(b, &configs); err != nil {
+		return err
+	}
+
+	coupons := make(StaticCoupons, len(configs))
+
+	for code, rc := range configs {
+		r, err := rc.rule()
+		if err != nil {
+			return fmt.Errorf("invalid rule of the coupon: %q, error: %s", code, err)
+		}
+
+		code = normalizeCouponCode(code)
+		coupons[code] = Coupon{Code: code, Rule: r}
+	}
+
+	*sc = coupons
+
+	return nil
+}
+
+// Coupon returns the coupon with the code.
+func (sc StaticCoupons) Coupon(ctx context.Context, code string) (Coupon, error) {
+	c, ok := sc[code]
+	if !ok {
+		return Coupon{}, errCouponNotFound
+	}
+
+	return c, nil
+}
+
+// WithCoupons sets CouponProvider used to look up coupons applied to Carts.
+func WithCoupons(p CouponProvider) Option {
+	return func(c *Carts) {
+		c.coupons = p
+	}
+}
+
+// normalizeCouponCode makes coupon codes case insensitive.
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// discount fills in Discounts, DiscountTotal and Total of a priced Cart. Coupons are applied in order,
+// the sum of discounts never exceeds the Cart subtotal. Coupons that are no longer available are ignored.
+func (c *Carts) discount(ctx context.Context, cart *Cart) error {
+	cart.Total = cart.Subtotal
+
+	if c.coupons == nil {
+		return nil
+	}
+
+	for _, code := range cart.Coupons {
+		coupon, err := c.coupons.Coupon(ctx, code)
+		if err != nil {
+			if err == errCouponNotFound {
+				continue
+			}
+			return fmt.Errorf("failed to get the coupon: %q, error: %s", code, err)
+		}
+
+		for _, d := range coupon.Rule.Apply(*cart) {
+			if d.Amount > cart.Total {
+				d.Amount = cart.Total
+			}
+			if d.Amount <= 0 {
+				continue
+			}
+
+			d.Code = code
+			cart.Discounts = append(cart.Discounts, d)
+			cart.DiscountTotal += d.Amount
+			cart.Total -= d.Amount
+		}
+	}
+
+	return nil
+}
+
+// ApplyCoupon to a Cart. Applying the same coupon again has no effect. Returns the priced Cart.
+func (c *Carts) ApplyCoupon(ctx context.Context, cartID int64, code string) (Cart, error) {
+	if err := c.authorize(ctx, cartID, RoleEditor); err != nil {
+		return Cart{}, err
+	}
+
+	code = normalizeCouponCode(code)
+	if code == "" {
+		return Cart{}, errInvalidCouponCode
+	}
+
+	if c.coupons == nil {
+		return Cart{}, errCouponNotFound
+	}
+
+	if _, err := c.coupons.Coupon(ctx, code); err != nil {
+		if err != errCouponNotFound {
+			log.Printf("Failed to get the coupon: %q, error: %s", code, err)
+		}
+		return Cart{}, err
+	}
+
+	if err := c.storage.AddCoupon(ctx, cartID, code, time.Now()); err != nil {
+		log.Printf("Failed to apply the coupon: %q to the Cart: %d, error: %s", code, cartID, err)
+		return Cart{}, err
+	}
+
+	c.changes.notify(cartID)
+
+	return c.Cart(ctx, cartID)
+}
+
+// RemoveCoupon from a Cart. Returns the priced Cart.
+func (c *Carts) RemoveCoupon(ctx context.Context, cartID int64, code string) (Cart, error) {
+	if err := c.authorize(ctx, cartID, RoleEditor); err != nil {
+		return Cart{}, err
+	}
+
+	code = normalizeCouponCode(code)
+
+	if err := c.storage.DeleteCoupon(ctx, cartID, code); err != nil {
+		log.Printf("Failed to remove the coupon: %q from the Cart: %d, error: %s", code, cartID, err)
+		return Cart{}, err
+	}
+
+	c.changes.notify(cartID)
+
+	return c.Cart(ctx, cartID)
+}
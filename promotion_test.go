@@ -0,0 +1,175 @@
+package cart
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRules(t *testing.T) {
+	cart := Cart{
+		Items: []LineItem{
+			{ProductID: 1, Quantity: 5, UnitPrice: 200, LineTotal: 1000},
+			{ProductID: 2, Quantity: 1, UnitPrice: 500, LineTotal: 500},
+			{ProductID: 3, Options: Options{"size": "M"}, Quantity: 2, UnitPrice: 300, LineTotal: 600},
+			{ProductID: 3, Options: Options{"size": "L"}, Quantity: 1, UnitPrice: 300, LineTotal: 300},
+		},
+		Subtotal: 2400,
+	}
+
+	cases := []struct {
+		name     string
+		rule     Rule
+		expected []Discount
+	}{
+		{
+			name:     "Percent off the Cart",
+			rule:     PercentOff{Percent: 10},
+			expected: []Discount{{Description: "10% off", Amount: 240}},
+		},
+		{
+			name:     "Percent off the Product",
+			rule:     PercentOff{Percent: 50, ProductID: 2},
+			expected: []Discount{{ProductID: 2, Description: "50% off the product", Amount: 250}},
+		},
+		{
+			name:     "Percent off the Product with options",
+			rule:     PercentOff{Percent: 50, ProductID: 3},
+			expected: []Discount{{ProductID: 3, Description: "50% off the product", Amount: 450}},
+		},
+		{
+			name:     "Percent off missing Product",
+			rule:     PercentOff{Percent: 50, ProductID: 4},
+			expected: nil,
+		},
+		{
+			name:     "Fixed off",
+			rule:     FixedOff{Amount: 300},
+			expected: []Discount{{Description: "300 off", Amount: 300}},
+		},
+		{
+			name:     "Buy 2 get 1",
+			rule:     BuyXGetY{ProductID: 1, Buy: 2, Get: 1},
+			expected: []Discount{{ProductID: 1, Description: "buy 2 get 1 free", Amount: 200}},
+		},
+		{
+			name:     "Buy 2 get 1 across options",
+			rule:     BuyXGetY{ProductID: 3, Buy: 2, Get: 1},
+			expected: []Discount{{ProductID: 3, Description: "buy 2 get 1 free", Amount: 300}},
+		},
+		{
+			name:     "Minimum subtotal reached",
+			rule:     MinSubtotal{Amount: 2400, Rule: FixedOff{Amount: 100}},
+			expected: []Discount{{Description: "100 off", Amount: 100}},
+		},
+		{
+			name:     "Minimum subtotal not reached",
+			rule:     MinSubtotal{Amount: 2401, Rule: FixedOff{Amount: 100}},
+			expected: nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.rule.Apply(cart)
+
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("Apply() mismatch (+got -want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiscount(t *testing.T) {
+	carts := New(nil, WithCoupons(StaticCoupons{
+		"TENOFF": {Code: "TENOFF", Rule: PercentOff{Percent: 10}},
+		"BIG":    {Code: "BIG", Rule: FixedOff{Amount: 5000}},
+	}))
+
+	cart := Cart{
+		Items:    []LineItem{{ProductID: 1, Quantity: 1, UnitPrice: 1000, LineTotal: 1000}},
+		Coupons:  []string{"TENOFF", "RETIRED", "BIG"},
+		Subtotal: 1000,
+	}
+
+	if err := carts.discount(context.Background(), &cart); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := []Discount{
+		{Code: "TENOFF", Description: "10% off", Amount: 100},
+		{Code: "BIG", Description: "5000 off", Amount: 900},
+	}
+
+	if diff := cmp.Diff(expected, cart.Discounts); diff != "" {
+		t.Errorf("Discounts mismatch (+got -want)\n%s", diff)
+	}
+
+	if cart.DiscountTotal != 1000 || cart.Total != 0 {
+		t.Errorf("Got discount total: %d and total: %d, expected: 1000 and 0", cart.DiscountTotal, cart.Total)
+	}
+}
+
+func TestApplyUnknownCoupon(t *testing.T) {
+	storage := &StorageMock{
+		AddCouponFunc: func(ctx context.Context, cartID int64, code string, ts time.Time) error {
+			t.Error("Unknown coupon applied")
+			return nil
+		},
+	}
+
+	carts := New(storage, WithCoupons(StaticCoupons{}))
+
+	if _, err := carts.ApplyCoupon(WithTrustedService(context.Background()), 1, " nope "); err != errCouponNotFound {
+		t.Errorf("Got error: %v, expected: %s", err, errCouponNotFound)
+	}
+
+	if _, err := carts.ApplyCoupon(WithTrustedService(context.Background()), 1, " "); err != errInvalidCouponCode {
+		t.Errorf("Got error: %v, expected: %s", err, errInvalidCouponCode)
+	}
+}
+
+func TestDecodeStaticCoupons(t *testing.T) {
+	input := `{
+		"spring10": {"type": "percent_off", "percent": 10},
+		"EGGS": {"type": "buy_x_get_y", "productId": 100, "buy": 2, "get": 1},
+		"BIG": {"type": "min_subtotal", "amount": 10000, "rule": {"type": "fixed_off", "amount": 500}}
+	}`
+
+	var coupons StaticCoupons
+	if err := json.Unmarshal([]byte(input), &coupons); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := StaticCoupons{
+		"SPRING10": {Code: "SPRING10", Rule: PercentOff{Percent: 10}},
+		"EGGS":     {Code: "EGGS", Rule: BuyXGetY{ProductID: 100, Buy: 2, Get: 1}},
+		"BIG":      {Code: "BIG", Rule: MinSubtotal{Amount: 10000, Rule: FixedOff{Amount: 500}}},
+	}
+
+	if diff := cmp.Diff(expected, coupons); diff != "" {
+		t.Errorf("StaticCoupons mismatch (+got -want)\n%s", diff)
+	}
+}
+
+func TestDecodeInvalidStaticCoupons(t *testing.T) {
+	for _, input := range []string{
+		`{"X": {"type": "free_lunch"}}`,
+		`{"X": {"type": "percent_off", "percent": 150}}`,
+		`{"X": {"type": "min_subtotal", "amount": 100}}`,
+		`{"X": {"type": "min_subtotal", "amount": 0, "rule": {"type": "fixed_off", "amount": 100}}}`,
+		`{"X": {"type": "fixed_off"}}`,
+		`{"X": {"type": "fixed_off", "amount": -100}}`,
+		`{"X": {"type": "buy_x_get_y", "productId": 1, "buy": 0, "get": 1}}`,
+		`{"X": {"type": "buy_x_get_y", "productId": 1, "buy": 2, "get": 0}}`,
+		`{"X": {"type": "buy_x_get_y", "buy": 2, "get": 1}}`,
+	} {
+		var coupons StaticCoupons
+		if err := json.Unmarshal([]byte(input), &coupons); err == nil {
+			t.Errorf("Got no error for coupons: %s", input)
+		}
+	}
+}
@@ -0,0 +1,3695 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: cart_service.proto
+
+package proto
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	empty "github.com/golang/protobuf/ptypes/empty"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// CartStatus is a state of the Cart in its lifecycle. Only open Carts can be modified.
+type CartStatus int32
+
+const (
+	CartStatus_STATUS_OPEN         CartStatus = 0
+	CartStatus_STATUS_CHECKING_OUT CartStatus = 1
+	CartStatus_STATUS_ORDERED      CartStatus = 2
+	CartStatus_STATUS_ABANDONED    CartStatus = 3
+)
+
+var CartStatus_name = map[int32]string{
+	0: "STATUS_OPEN",
+	1: "STATUS_CHECKING_OUT",
+	2: "STATUS_ORDERED",
+	3: "STATUS_ABANDONED",
+}
+
+var CartStatus_value = map[string]int32{
+	"STATUS_OPEN":         0,
+	"STATUS_CHECKING_OUT": 1,
+	"STATUS_ORDERED":      2,
+	"STATUS_ABANDONED":    3,
+}
+
+func (x CartStatus) String() string {
+	return proto.EnumName(CartStatus_name, int32(x))
+}
+
+func (CartStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{0}
+}
+
+// MergeStrategy defines how quantities are combined when both Carts contain the same Product.
+type MergeStrategy int32
+
+const (
+	// MERGE_SUM adds source quantity to the target one.
+	MergeStrategy_MERGE_SUM MergeStrategy = 0
+	// MERGE_MAX keeps the largest of two quantities.
+	MergeStrategy_MERGE_MAX MergeStrategy = 1
+	// MERGE_PREFER_SOURCE replaces target quantity with the source one.
+	MergeStrategy_MERGE_PREFER_SOURCE MergeStrategy = 2
+)
+
+var MergeStrategy_name = map[int32]string{
+	0: "MERGE_SUM",
+	1: "MERGE_MAX",
+	2: "MERGE_PREFER_SOURCE",
+}
+
+var MergeStrategy_value = map[string]int32{
+	"MERGE_SUM":           0,
+	"MERGE_MAX":           1,
+	"MERGE_PREFER_SOURCE": 2,
+}
+
+func (x MergeStrategy) String() string {
+	return proto.EnumName(MergeStrategy_name, int32(x))
+}
+
+func (MergeStrategy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{1}
+}
+
+// MemberRole defines what a member can do with a shared Cart.
+type MemberRole int32
+
+const (
+	// MEMBER_ROLE_UNSPECIFIED is not a valid role, it is rejected by InviteMember.
+	MemberRole_MEMBER_ROLE_UNSPECIFIED MemberRole = 0
+	// MEMBER_ROLE_VIEWER can only read the Cart.
+	MemberRole_MEMBER_ROLE_VIEWER MemberRole = 1
+	// MEMBER_ROLE_EDITOR can change LineItems and coupons of the Cart.
+	MemberRole_MEMBER_ROLE_EDITOR MemberRole = 2
+	// MEMBER_ROLE_OWNER can also delete and check out the Cart and manage its members.
+	MemberRole_MEMBER_ROLE_OWNER MemberRole = 3
+)
+
+var MemberRole_name = map[int32]string{
+	0: "MEMBER_ROLE_UNSPECIFIED",
+	1: "MEMBER_ROLE_VIEWER",
+	2: "MEMBER_ROLE_EDITOR",
+	3: "MEMBER_ROLE_OWNER",
+}
+
+var MemberRole_value = map[string]int32{
+	"MEMBER_ROLE_UNSPECIFIED": 0,
+	"MEMBER_ROLE_VIEWER":      1,
+	"MEMBER_ROLE_EDITOR":      2,
+	"MEMBER_ROLE_OWNER":       3,
+}
+
+func (x MemberRole) String() string {
+	return proto.EnumName(MemberRole_name, int32(x))
+}
+
+func (MemberRole) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{2}
+}
+
+type CartOperation_Type int32
+
+const (
+	// ADD adds quantity units of the Product.
+	CartOperation_ADD CartOperation_Type = 0
+	// SET replaces quantity of the Product, zero quantity removes the Product.
+	CartOperation_SET CartOperation_Type = 1
+	// REMOVE removes quantity units of the Product or the whole LineItem when quantity is zero.
+	CartOperation_REMOVE CartOperation_Type = 2
+	// EMPTY removes all LineItems.
+	CartOperation_EMPTY CartOperation_Type = 3
+)
+
+var CartOperation_Type_name = map[int32]string{
+	0: "ADD",
+	1: "SET",
+	2: "REMOVE",
+	3: "EMPTY",
+}
+
+var CartOperation_Type_value = map[string]int32{
+	"ADD":    0,
+	"SET":    1,
+	"REMOVE": 2,
+	"EMPTY":  3,
+}
+
+func (x CartOperation_Type) String() string {
+	return proto.EnumName(CartOperation_Type_name, int32(x))
+}
+
+func (CartOperation_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{21, 0}
+}
+
+// LineItem represents an SKU with quantity.
+type LineItem struct {
+	ProductId int64  `protobuf:"varint,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	Quantity  uint32 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// unitPrice and lineTotal are in minor units of the Cart currency, e.g. cents.
+	UnitPrice int64 `protobuf:"varint,3,opt,name=unitPrice,proto3" json:"unitPrice,omitempty"`
+	LineTotal int64 `protobuf:"varint,4,opt,name=lineTotal,proto3" json:"lineTotal,omitempty"`
+	// reservationId identifies stock reserved for the LineItem while the Cart is being checked out.
+	ReservationId string `protobuf:"bytes,5,opt,name=reservationId,proto3" json:"reservationId,omitempty"`
+	// options, e.g. size and color, make separate LineItems of the same Product.
+	Options map[string]string `protobuf:"bytes,6,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// note is a free-form comment of the User, e.g. a gift message.
+	Note                 string   `protobuf:"bytes,7,opt,name=note,proto3" json:"note,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LineItem) Reset()         { *m = LineItem{} }
+func (m *LineItem) String() string { return proto.CompactTextString(m) }
+func (*LineItem) ProtoMessage()    {}
+func (*LineItem) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{0}
+}
+
+func (m *LineItem) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LineItem.Unmarshal(m, b)
+}
+func (m *LineItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LineItem.Marshal(b, m, deterministic)
+}
+func (m *LineItem) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LineItem.Merge(m, src)
+}
+func (m *LineItem) XXX_Size() int {
+	return xxx_messageInfo_LineItem.Size(m)
+}
+func (m *LineItem) XXX_DiscardUnknown() {
+	xxx_messageInfo_LineItem.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LineItem proto.InternalMessageInfo
+
+func (m *LineItem) GetProductId() int64 {
+	if m != nil {
+		return m.ProductId
+	}
+	return 0
+}
+
+func (m *LineItem) GetQuantity() uint32 {
+	if m != nil {
+		return m.Quantity
+	}
+	return 0
+}
+
+func (m *LineItem) GetUnitPrice() int64 {
+	if m != nil {
+		return m.UnitPrice
+	}
+	return 0
+}
+
+func (m *LineItem) GetLineTotal() int64 {
+	if m != nil {
+		return m.LineTotal
+	}
+	return 0
+}
+
+func (m *LineItem) GetReservationId() string {
+	if m != nil {
+		return m.ReservationId
+	}
+	return ""
+}
+
+func (m *LineItem) GetOptions() map[string]string {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *LineItem) GetNote() string {
+	if m != nil {
+		return m.Note
+	}
+	return ""
+}
+
+// Cart holds selected LineItems.
+type Cart struct {
+	Id        int64                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId    int64                `protobuf:"varint,2,opt,name=userId,proto3" json:"userId,omitempty"`
+	CreatedAt *timestamp.Timestamp `protobuf:"bytes,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	UpdatedAt *timestamp.Timestamp `protobuf:"bytes,4,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	Items     []*LineItem          `protobuf:"bytes,5,rep,name=items,proto3" json:"items,omitempty"`
+	Status    CartStatus           `protobuf:"varint,6,opt,name=status,proto3,enum=cooldryplace.protobuf.CartStatus" json:"status,omitempty"`
+	// version is incremented by every modification of the Cart.
+	Version int64 `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
+	// subtotal is a sum of LineItem totals in minor units of the currency.
+	Subtotal int64 `protobuf:"varint,8,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	// currency is an ISO 4217 code, empty when the Cart is not priced.
+	Currency string `protobuf:"bytes,9,opt,name=currency,proto3" json:"currency,omitempty"`
+	// coupons are codes applied to the Cart in order of application.
+	Coupons []string `protobuf:"bytes,10,rep,name=coupons,proto3" json:"coupons,omitempty"`
+	// discounts of the applied coupons, their sum is discountTotal.
+	Discounts     []*Discount `protobuf:"bytes,11,rep,name=discounts,proto3" json:"discounts,omitempty"`
+	DiscountTotal int64       `protobuf:"varint,12,opt,name=discountTotal,proto3" json:"discountTotal,omitempty"`
+	// total is the subtotal with discounts taken off.
+	Total int64 `protobuf:"varint,13,opt,name=total,proto3" json:"total,omitempty"`
+	// savedItems are parked by the User, they are not priced and not ordered.
+	SavedItems           []*LineItem `protobuf:"bytes,14,rep,name=savedItems,proto3" json:"savedItems,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *Cart) Reset()         { *m = Cart{} }
+func (m *Cart) String() string { return proto.CompactTextString(m) }
+func (*Cart) ProtoMessage()    {}
+func (*Cart) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{1}
+}
+
+func (m *Cart) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Cart.Unmarshal(m, b)
+}
+func (m *Cart) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Cart.Marshal(b, m, deterministic)
+}
+func (m *Cart) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Cart.Merge(m, src)
+}
+func (m *Cart) XXX_Size() int {
+	return xxx_messageInfo_Cart.Size(m)
+}
+func (m *Cart) XXX_DiscardUnknown() {
+	xxx_messageInfo_Cart.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Cart proto.InternalMessageInfo
+
+func (m *Cart) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Cart) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *Cart) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *Cart) GetUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+func (m *Cart) GetItems() []*LineItem {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func (m *Cart) GetStatus() CartStatus {
+	if m != nil {
+		return m.Status
+	}
+	return CartStatus_STATUS_OPEN
+}
+
+func (m *Cart) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Cart) GetSubtotal() int64 {
+	if m != nil {
+		return m.Subtotal
+	}
+	return 0
+}
+
+func (m *Cart) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *Cart) GetCoupons() []string {
+	if m != nil {
+		return m.Coupons
+	}
+	return nil
+}
+
+func (m *Cart) GetDiscounts() []*Discount {
+	if m != nil {
+		return m.Discounts
+	}
+	return nil
+}
+
+func (m *Cart) GetDiscountTotal() int64 {
+	if m != nil {
+		return m.DiscountTotal
+	}
+	return 0
+}
+
+func (m *Cart) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *Cart) GetSavedItems() []*LineItem {
+	if m != nil {
+		return m.SavedItems
+	}
+	return nil
+}
+
+// Discount is a part of the Cart price taken off by a coupon.
+type Discount struct {
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// productId is zero for discounts applied to the whole Cart.
+	ProductId            int64    `protobuf:"varint,2,opt,name=productId,proto3" json:"productId,omitempty"`
+	Description          string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Amount               int64    `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Discount) Reset()         { *m = Discount{} }
+func (m *Discount) String() string { return proto.CompactTextString(m) }
+func (*Discount) ProtoMessage()    {}
+func (*Discount) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{2}
+}
+
+func (m *Discount) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Discount.Unmarshal(m, b)
+}
+func (m *Discount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Discount.Marshal(b, m, deterministic)
+}
+func (m *Discount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Discount.Merge(m, src)
+}
+func (m *Discount) XXX_Size() int {
+	return xxx_messageInfo_Discount.Size(m)
+}
+func (m *Discount) XXX_DiscardUnknown() {
+	xxx_messageInfo_Discount.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Discount proto.InternalMessageInfo
+
+func (m *Discount) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *Discount) GetProductId() int64 {
+	if m != nil {
+		return m.ProductId
+	}
+	return 0
+}
+
+func (m *Discount) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *Discount) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+// CartCreateRequest is used to create new Cart for User ID.
+type CartCreateRequest struct {
+	UserId int64 `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	// idempotencyKey makes retries of the request return the original result without applying the change again.
+	// It can also be provided with "idempotency-key" gRPC metadata.
+	IdempotencyKey       string   `protobuf:"bytes,2,opt,name=idempotencyKey,proto3" json:"idempotencyKey,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CartCreateRequest) Reset()         { *m = CartCreateRequest{} }
+func (m *CartCreateRequest) String() string { return proto.CompactTextString(m) }
+func (*CartCreateRequest) ProtoMessage()    {}
+func (*CartCreateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{3}
+}
+
+func (m *CartCreateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CartCreateRequest.Unmarshal(m, b)
+}
+func (m *CartCreateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CartCreateRequest.Marshal(b, m, deterministic)
+}
+func (m *CartCreateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CartCreateRequest.Merge(m, src)
+}
+func (m *CartCreateRequest) XXX_Size() int {
+	return xxx_messageInfo_CartCreateRequest.Size(m)
+}
+func (m *CartCreateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CartCreateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CartCreateRequest proto.InternalMessageInfo
+
+func (m *CartCreateRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *CartCreateRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+// CartRequest is used to fetch data about Cart state.
+type CartRequest struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CartRequest) Reset()         { *m = CartRequest{} }
+func (m *CartRequest) String() string { return proto.CompactTextString(m) }
+func (*CartRequest) ProtoMessage()    {}
+func (*CartRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{4}
+}
+
+func (m *CartRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CartRequest.Unmarshal(m, b)
+}
+func (m *CartRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CartRequest.Marshal(b, m, deterministic)
+}
+func (m *CartRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CartRequest.Merge(m, src)
+}
+func (m *CartRequest) XXX_Size() int {
+	return xxx_messageInfo_CartRequest.Size(m)
+}
+func (m *CartRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CartRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CartRequest proto.InternalMessageInfo
+
+func (m *CartRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+// CartResponse is used to return Cart details.
+type CartResponse struct {
+	Cart                 *Cart    `protobuf:"bytes,1,opt,name=cart,proto3" json:"cart,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CartResponse) Reset()         { *m = CartResponse{} }
+func (m *CartResponse) String() string { return proto.CompactTextString(m) }
+func (*CartResponse) ProtoMessage()    {}
+func (*CartResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{5}
+}
+
+func (m *CartResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CartResponse.Unmarshal(m, b)
+}
+func (m *CartResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CartResponse.Marshal(b, m, deterministic)
+}
+func (m *CartResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CartResponse.Merge(m, src)
+}
+func (m *CartResponse) XXX_Size() int {
+	return xxx_messageInfo_CartResponse.Size(m)
+}
+func (m *CartResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CartResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CartResponse proto.InternalMessageInfo
+
+func (m *CartResponse) GetCart() *Cart {
+	if m != nil {
+		return m.Cart
+	}
+	return nil
+}
+
+// CartDeleteRequest is used to identify Cart that should be removed.
+type CartDeleteRequest struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CartDeleteRequest) Reset()         { *m = CartDeleteRequest{} }
+func (m *CartDeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*CartDeleteRequest) ProtoMessage()    {}
+func (*CartDeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{6}
+}
+
+func (m *CartDeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CartDeleteRequest.Unmarshal(m, b)
+}
+func (m *CartDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CartDeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *CartDeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CartDeleteRequest.Merge(m, src)
+}
+func (m *CartDeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_CartDeleteRequest.Size(m)
+}
+func (m *CartDeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CartDeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CartDeleteRequest proto.InternalMessageInfo
+
+func (m *CartDeleteRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+// AddProductRequest provides a way to specify what SKU should be added to a Cart.
+type AddProductRequest struct {
+	ProductId int64  `protobuf:"varint,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	Quantity  uint32 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	CartId    int64  `protobuf:"varint,3,opt,name=cartId,proto3" json:"cartId,omitempty"`
+	// expectedVersion if set makes the request fail unless the Cart has this version.
+	ExpectedVersion int64 `protobuf:"varint,4,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	// idempotencyKey makes retries of the request return the original result without applying the change again.
+	// It can also be provided with "idempotency-key" gRPC metadata.
+	IdempotencyKey string `protobuf:"bytes,5,opt,name=idempotencyKey,proto3" json:"idempotencyKey,omitempty"`
+	// options identify the LineItem together with productId. Keys are case insensitive, empty values are ignored.
+	Options map[string]string `protobuf:"bytes,6,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// note replaces the one of the LineItem unless it is empty.
+	Note                 string   `protobuf:"bytes,7,opt,name=note,proto3" json:"note,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddProductRequest) Reset()         { *m = AddProductRequest{} }
+func (m *AddProductRequest) String() string { return proto.CompactTextString(m) }
+func (*AddProductRequest) ProtoMessage()    {}
+func (*AddProductRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{7}
+}
+
+func (m *AddProductRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddProductRequest.Unmarshal(m, b)
+}
+func (m *AddProductRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddProductRequest.Marshal(b, m, deterministic)
+}
+func (m *AddProductRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddProductRequest.Merge(m, src)
+}
+func (m *AddProductRequest) XXX_Size() int {
+	return xxx_messageInfo_AddProductRequest.Size(m)
+}
+func (m *AddProductRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddProductRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddProductRequest proto.InternalMessageInfo
+
+func (m *AddProductRequest) GetProductId() int64 {
+	if m != nil {
+		return m.ProductId
+	}
+	return 0
+}
+
+func (m *AddProductRequest) GetQuantity() uint32 {
+	if m != nil {
+		return m.Quantity
+	}
+	return 0
+}
+
+func (m *AddProductRequest) GetCartId() int64 {
+	if m != nil {
+		return m.CartId
+	}
+	return 0
+}
+
+func (m *AddProductRequest) GetExpectedVersion() int64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+func (m *AddProductRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+func (m *AddProductRequest) GetOptions() map[string]string {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *AddProductRequest) GetNote() string {
+	if m != nil {
+		return m.Note
+	}
+	return ""
+}
+
+// DelProductRequest provides data to identify Cart that needs to be deleted.
+type DelProductRequest struct {
+	ProductId int64 `protobuf:"varint,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	CartId    int64 `protobuf:"varint,2,opt,name=cartId,proto3" json:"cartId,omitempty"`
+	// expectedVersion if set makes the request fail unless the Cart has this version.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	// idempotencyKey makes retries of the request return the original result without applying the change again.
+	// It can also be provided with "idempotency-key" gRPC metadata.
+	IdempotencyKey string `protobuf:"bytes,4,opt,name=idempotencyKey,proto3" json:"idempotencyKey,omitempty"`
+	// options identify the LineItem together with productId.
+	Options              map[string]string `protobuf:"bytes,5,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *DelProductRequest) Reset()         { *m = DelProductRequest{} }
+func (m *DelProductRequest) String() string { return proto.CompactTextString(m) }
+func (*DelProductRequest) ProtoMessage()    {}
+func (*DelProductRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{8}
+}
+
+func (m *DelProductRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DelProductRequest.Unmarshal(m, b)
+}
+func (m *DelProductRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DelProductRequest.Marshal(b, m, deterministic)
+}
+func (m *DelProductRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DelProductRequest.Merge(m, src)
+}
+func (m *DelProductRequest) XXX_Size() int {
+	return xxx_messageInfo_DelProductRequest.Size(m)
+}
+func (m *DelProductRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DelProductRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DelProductRequest proto.InternalMessageInfo
+
+func (m *DelProductRequest) GetProductId() int64 {
+	if m != nil {
+		return m.ProductId
+	}
+	return 0
+}
+
+func (m *DelProductRequest) GetCartId() int64 {
+	if m != nil {
+		return m.CartId
+	}
+	return 0
+}
+
+func (m *DelProductRequest) GetExpectedVersion() int64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+func (m *DelProductRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+func (m *DelProductRequest) GetOptions() map[string]string {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+// EmptyCartRequest is used to remove all LineItems from a Cart.
+type EmptyCartRequest struct {
+	CartId int64 `protobuf:"varint,1,opt,name=cartId,proto3" json:"cartId,omitempty"`
+	// expectedVersion if set makes the request fail unless the Cart has this version.
+	ExpectedVersion int64 `protobuf:"varint,2,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	// idempotencyKey makes retries of the request return the original result without applying the change again.
+	// It can also be provided with "idempotency-key" gRPC metadata.
+	IdempotencyKey       string   `protobuf:"bytes,3,opt,name=idempotencyKey,proto3" json:"idempotencyKey,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EmptyCartRequest) Reset()         { *m = EmptyCartRequest{} }
+func (m *EmptyCartRequest) String() string { return proto.CompactTextString(m) }
+func (*EmptyCartRequest) ProtoMessage()    {}
+func (*EmptyCartRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{9}
+}
+
+func (m *EmptyCartRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EmptyCartRequest.Unmarshal(m, b)
+}
+func (m *EmptyCartRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EmptyCartRequest.Marshal(b, m, deterministic)
+}
+func (m *EmptyCartRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EmptyCartRequest.Merge(m, src)
+}
+func (m *EmptyCartRequest) XXX_Size() int {
+	return xxx_messageInfo_EmptyCartRequest.Size(m)
+}
+func (m *EmptyCartRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EmptyCartRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EmptyCartRequest proto.InternalMessageInfo
+
+func (m *EmptyCartRequest) GetCartId() int64 {
+	if m != nil {
+		return m.CartId
+	}
+	return 0
+}
+
+func (m *EmptyCartRequest) GetExpectedVersion() int64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+func (m *EmptyCartRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+// SetProductQuantityRequest provides a new quantity of SKU in a Cart. Zero quantity removes the LineItem.
+type SetProductQuantityRequest struct {
+	ProductId int64  `protobuf:"varint,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	Quantity  uint32 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	CartId    int64  `protobuf:"varint,3,opt,name=cartId,proto3" json:"cartId,omitempty"`
+	// expectedVersion if set makes the request fail unless the Cart has this version.
+	ExpectedVersion int64 `protobuf:"varint,4,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	// options identify the LineItem together with productId.
+	Options              map[string]string `protobuf:"bytes,5,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *SetProductQuantityRequest) Reset()         { *m = SetProductQuantityRequest{} }
+func (m *SetProductQuantityRequest) String() string { return proto.CompactTextString(m) }
+func (*SetProductQuantityRequest) ProtoMessage()    {}
+func (*SetProductQuantityRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{10}
+}
+
+func (m *SetProductQuantityRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetProductQuantityRequest.Unmarshal(m, b)
+}
+func (m *SetProductQuantityRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetProductQuantityRequest.Marshal(b, m, deterministic)
+}
+func (m *SetProductQuantityRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetProductQuantityRequest.Merge(m, src)
+}
+func (m *SetProductQuantityRequest) XXX_Size() int {
+	return xxx_messageInfo_SetProductQuantityRequest.Size(m)
+}
+func (m *SetProductQuantityRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetProductQuantityRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetProductQuantityRequest proto.InternalMessageInfo
+
+func (m *SetProductQuantityRequest) GetProductId() int64 {
+	if m != nil {
+		return m.ProductId
+	}
+	return 0
+}
+
+func (m *SetProductQuantityRequest) GetQuantity() uint32 {
+	if m != nil {
+		return m.Quantity
+	}
+	return 0
+}
+
+func (m *SetProductQuantityRequest) GetCartId() int64 {
+	if m != nil {
+		return m.CartId
+	}
+	return 0
+}
+
+func (m *SetProductQuantityRequest) GetExpectedVersion() int64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+func (m *SetProductQuantityRequest) GetOptions() map[string]string {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+// RemoveProductUnitsRequest specifies how many units of SKU should be removed from a Cart.
+type RemoveProductUnitsRequest struct {
+	ProductId int64  `protobuf:"varint,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	Quantity  uint32 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	CartId    int64  `protobuf:"varint,3,opt,name=cartId,proto3" json:"cartId,omitempty"`
+	// expectedVersion if set makes the request fail unless the Cart has this version.
+	ExpectedVersion int64 `protobuf:"varint,4,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	// options identify the LineItem together with productId.
+	Options              map[string]string `protobuf:"bytes,5,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *RemoveProductUnitsRequest) Reset()         { *m = RemoveProductUnitsRequest{} }
+func (m *RemoveProductUnitsRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveProductUnitsRequest) ProtoMessage()    {}
+func (*RemoveProductUnitsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{11}
+}
+
+func (m *RemoveProductUnitsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveProductUnitsRequest.Unmarshal(m, b)
+}
+func (m *RemoveProductUnitsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveProductUnitsRequest.Marshal(b, m, deterministic)
+}
+func (m *RemoveProductUnitsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveProductUnitsRequest.Merge(m, src)
+}
+func (m *RemoveProductUnitsRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveProductUnitsRequest.Size(m)
+}
+func (m *RemoveProductUnitsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveProductUnitsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveProductUnitsRequest proto.InternalMessageInfo
+
+func (m *RemoveProductUnitsRequest) GetProductId() int64 {
+	if m != nil {
+		return m.ProductId
+	}
+	return 0
+}
+
+func (m *RemoveProductUnitsRequest) GetQuantity() uint32 {
+	if m != nil {
+		return m.Quantity
+	}
+	return 0
+}
+
+func (m *RemoveProductUnitsRequest) GetCartId() int64 {
+	if m != nil {
+		return m.CartId
+	}
+	return 0
+}
+
+func (m *RemoveProductUnitsRequest) GetExpectedVersion() int64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+func (m *RemoveProductUnitsRequest) GetOptions() map[string]string {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+// ListCartsRequest is used to fetch a page of User Carts. Empty pageToken requests the first page.
+type ListCartsRequest struct {
+	UserId               int64    `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	PageSize             int32    `protobuf:"varint,2,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	PageToken            string   `protobuf:"bytes,3,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListCartsRequest) Reset()         { *m = ListCartsRequest{} }
+func (m *ListCartsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListCartsRequest) ProtoMessage()    {}
+func (*ListCartsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{12}
+}
+
+func (m *ListCartsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListCartsRequest.Unmarshal(m, b)
+}
+func (m *ListCartsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListCartsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListCartsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListCartsRequest.Merge(m, src)
+}
+func (m *ListCartsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListCartsRequest.Size(m)
+}
+func (m *ListCartsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListCartsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListCartsRequest proto.InternalMessageInfo
+
+func (m *ListCartsRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *ListCartsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListCartsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+// ListCartsResponse contains a page of Carts. nextPageToken is empty on the last page.
+type ListCartsResponse struct {
+	Carts                []*Cart  `protobuf:"bytes,1,rep,name=carts,proto3" json:"carts,omitempty"`
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListCartsResponse) Reset()         { *m = ListCartsResponse{} }
+func (m *ListCartsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListCartsResponse) ProtoMessage()    {}
+func (*ListCartsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{13}
+}
+
+func (m *ListCartsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListCartsResponse.Unmarshal(m, b)
+}
+func (m *ListCartsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListCartsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListCartsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListCartsResponse.Merge(m, src)
+}
+func (m *ListCartsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListCartsResponse.Size(m)
+}
+func (m *ListCartsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListCartsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListCartsResponse proto.InternalMessageInfo
+
+func (m *ListCartsResponse) GetCarts() []*Cart {
+	if m != nil {
+		return m.Carts
+	}
+	return nil
+}
+
+func (m *ListCartsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// ActiveCartRequest is used to fetch the Cart User is working with.
+type ActiveCartRequest struct {
+	UserId               int64    `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ActiveCartRequest) Reset()         { *m = ActiveCartRequest{} }
+func (m *ActiveCartRequest) String() string { return proto.CompactTextString(m) }
+func (*ActiveCartRequest) ProtoMessage()    {}
+func (*ActiveCartRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{14}
+}
+
+func (m *ActiveCartRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ActiveCartRequest.Unmarshal(m, b)
+}
+func (m *ActiveCartRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ActiveCartRequest.Marshal(b, m, deterministic)
+}
+func (m *ActiveCartRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActiveCartRequest.Merge(m, src)
+}
+func (m *ActiveCartRequest) XXX_Size() int {
+	return xxx_messageInfo_ActiveCartRequest.Size(m)
+}
+func (m *ActiveCartRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActiveCartRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActiveCartRequest proto.InternalMessageInfo
+
+func (m *ActiveCartRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+// MergeCartsRequest is used to merge a guest Cart into a User Cart, usually on login.
+type MergeCartsRequest struct {
+	SourceCartId int64         `protobuf:"varint,1,opt,name=sourceCartId,proto3" json:"sourceCartId,omitempty"`
+	TargetCartId int64         `protobuf:"varint,2,opt,name=targetCartId,proto3" json:"targetCartId,omitempty"`
+	Strategy     MergeStrategy `protobuf:"varint,3,opt,name=strategy,proto3,enum=cooldryplace.protobuf.MergeStrategy" json:"strategy,omitempty"`
+	// expectedVersion if set makes the request fail unless the target Cart has this version.
+	ExpectedVersion      int64    `protobuf:"varint,4,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MergeCartsRequest) Reset()         { *m = MergeCartsRequest{} }
+func (m *MergeCartsRequest) String() string { return proto.CompactTextString(m) }
+func (*MergeCartsRequest) ProtoMessage()    {}
+func (*MergeCartsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{15}
+}
+
+func (m *MergeCartsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MergeCartsRequest.Unmarshal(m, b)
+}
+func (m *MergeCartsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MergeCartsRequest.Marshal(b, m, deterministic)
+}
+func (m *MergeCartsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MergeCartsRequest.Merge(m, src)
+}
+func (m *MergeCartsRequest) XXX_Size() int {
+	return xxx_messageInfo_MergeCartsRequest.Size(m)
+}
+func (m *MergeCartsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MergeCartsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MergeCartsRequest proto.InternalMessageInfo
+
+func (m *MergeCartsRequest) GetSourceCartId() int64 {
+	if m != nil {
+		return m.SourceCartId
+	}
+	return 0
+}
+
+func (m *MergeCartsRequest) GetTargetCartId() int64 {
+	if m != nil {
+		return m.TargetCartId
+	}
+	return 0
+}
+
+func (m *MergeCartsRequest) GetStrategy() MergeStrategy {
+	if m != nil {
+		return m.Strategy
+	}
+	return MergeStrategy_MERGE_SUM
+}
+
+func (m *MergeCartsRequest) GetExpectedVersion() int64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+// SavedItemRequest identifies a Product moved between Cart items and saved items.
+type SavedItemRequest struct {
+	CartId    int64 `protobuf:"varint,1,opt,name=cartId,proto3" json:"cartId,omitempty"`
+	ProductId int64 `protobuf:"varint,2,opt,name=productId,proto3" json:"productId,omitempty"`
+	// expectedVersion if set makes the request fail unless the Cart has this version.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	// options identify the LineItem together with productId.
+	Options              map[string]string `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *SavedItemRequest) Reset()         { *m = SavedItemRequest{} }
+func (m *SavedItemRequest) String() string { return proto.CompactTextString(m) }
+func (*SavedItemRequest) ProtoMessage()    {}
+func (*SavedItemRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{16}
+}
+
+func (m *SavedItemRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SavedItemRequest.Unmarshal(m, b)
+}
+func (m *SavedItemRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SavedItemRequest.Marshal(b, m, deterministic)
+}
+func (m *SavedItemRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SavedItemRequest.Merge(m, src)
+}
+func (m *SavedItemRequest) XXX_Size() int {
+	return xxx_messageInfo_SavedItemRequest.Size(m)
+}
+func (m *SavedItemRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SavedItemRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SavedItemRequest proto.InternalMessageInfo
+
+func (m *SavedItemRequest) GetCartId() int64 {
+	if m != nil {
+		return m.CartId
+	}
+	return 0
+}
+
+func (m *SavedItemRequest) GetProductId() int64 {
+	if m != nil {
+		return m.ProductId
+	}
+	return 0
+}
+
+func (m *SavedItemRequest) GetExpectedVersion() int64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+func (m *SavedItemRequest) GetOptions() map[string]string {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+// CouponRequest identifies a coupon code of a Cart.
+type CouponRequest struct {
+	CartId int64  `protobuf:"varint,1,opt,name=cartId,proto3" json:"cartId,omitempty"`
+	Code   string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	// expectedVersion if set makes the request fail unless the Cart has this version.
+	ExpectedVersion      int64    `protobuf:"varint,3,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CouponRequest) Reset()         { *m = CouponRequest{} }
+func (m *CouponRequest) String() string { return proto.CompactTextString(m) }
+func (*CouponRequest) ProtoMessage()    {}
+func (*CouponRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{17}
+}
+
+func (m *CouponRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CouponRequest.Unmarshal(m, b)
+}
+func (m *CouponRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CouponRequest.Marshal(b, m, deterministic)
+}
+func (m *CouponRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CouponRequest.Merge(m, src)
+}
+func (m *CouponRequest) XXX_Size() int {
+	return xxx_messageInfo_CouponRequest.Size(m)
+}
+func (m *CouponRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CouponRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CouponRequest proto.InternalMessageInfo
+
+func (m *CouponRequest) GetCartId() int64 {
+	if m != nil {
+		return m.CartId
+	}
+	return 0
+}
+
+func (m *CouponRequest) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *CouponRequest) GetExpectedVersion() int64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+// CartHistoryRequest is used to fetch a page of the Cart changes. Empty pageToken requests the first page.
+type CartHistoryRequest struct {
+	CartId               int64    `protobuf:"varint,1,opt,name=cartId,proto3" json:"cartId,omitempty"`
+	PageSize             int32    `protobuf:"varint,2,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	PageToken            string   `protobuf:"bytes,3,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CartHistoryRequest) Reset()         { *m = CartHistoryRequest{} }
+func (m *CartHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*CartHistoryRequest) ProtoMessage()    {}
+func (*CartHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{18}
+}
+
+func (m *CartHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CartHistoryRequest.Unmarshal(m, b)
+}
+func (m *CartHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CartHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *CartHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CartHistoryRequest.Merge(m, src)
+}
+func (m *CartHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_CartHistoryRequest.Size(m)
+}
+func (m *CartHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CartHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CartHistoryRequest proto.InternalMessageInfo
+
+func (m *CartHistoryRequest) GetCartId() int64 {
+	if m != nil {
+		return m.CartId
+	}
+	return 0
+}
+
+func (m *CartHistoryRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *CartHistoryRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+// HistoryEntry is a change of a Cart. Quantities are set for changes of a Product quantity,
+// detail describes other changes, e.g. new Cart status or a coupon code. Empty actor means unknown caller.
+type HistoryEntry struct {
+	Id                   int64                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CartId               int64                `protobuf:"varint,2,opt,name=cartId,proto3" json:"cartId,omitempty"`
+	Actor                string               `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	Operation            string               `protobuf:"bytes,4,opt,name=operation,proto3" json:"operation,omitempty"`
+	ProductId            int64                `protobuf:"varint,5,opt,name=productId,proto3" json:"productId,omitempty"`
+	OldQuantity          uint32               `protobuf:"varint,6,opt,name=oldQuantity,proto3" json:"oldQuantity,omitempty"`
+	NewQuantity          uint32               `protobuf:"varint,7,opt,name=newQuantity,proto3" json:"newQuantity,omitempty"`
+	Detail               string               `protobuf:"bytes,8,opt,name=detail,proto3" json:"detail,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,9,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *HistoryEntry) Reset()         { *m = HistoryEntry{} }
+func (m *HistoryEntry) String() string { return proto.CompactTextString(m) }
+func (*HistoryEntry) ProtoMessage()    {}
+func (*HistoryEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{19}
+}
+
+func (m *HistoryEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryEntry.Unmarshal(m, b)
+}
+func (m *HistoryEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryEntry.Marshal(b, m, deterministic)
+}
+func (m *HistoryEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryEntry.Merge(m, src)
+}
+func (m *HistoryEntry) XXX_Size() int {
+	return xxx_messageInfo_HistoryEntry.Size(m)
+}
+func (m *HistoryEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryEntry proto.InternalMessageInfo
+
+func (m *HistoryEntry) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *HistoryEntry) GetCartId() int64 {
+	if m != nil {
+		return m.CartId
+	}
+	return 0
+}
+
+func (m *HistoryEntry) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *HistoryEntry) GetOperation() string {
+	if m != nil {
+		return m.Operation
+	}
+	return ""
+}
+
+func (m *HistoryEntry) GetProductId() int64 {
+	if m != nil {
+		return m.ProductId
+	}
+	return 0
+}
+
+func (m *HistoryEntry) GetOldQuantity() uint32 {
+	if m != nil {
+		return m.OldQuantity
+	}
+	return 0
+}
+
+func (m *HistoryEntry) GetNewQuantity() uint32 {
+	if m != nil {
+		return m.NewQuantity
+	}
+	return 0
+}
+
+func (m *HistoryEntry) GetDetail() string {
+	if m != nil {
+		return m.Detail
+	}
+	return ""
+}
+
+func (m *HistoryEntry) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+// CartHistoryResponse contains a page of the Cart changes. nextPageToken is empty on the last page.
+type CartHistoryResponse struct {
+	Entries              []*HistoryEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	NextPageToken        string          `protobuf:"bytes,2,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *CartHistoryResponse) Reset()         { *m = CartHistoryResponse{} }
+func (m *CartHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*CartHistoryResponse) ProtoMessage()    {}
+func (*CartHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{20}
+}
+
+func (m *CartHistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CartHistoryResponse.Unmarshal(m, b)
+}
+func (m *CartHistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CartHistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *CartHistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CartHistoryResponse.Merge(m, src)
+}
+func (m *CartHistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_CartHistoryResponse.Size(m)
+}
+func (m *CartHistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CartHistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CartHistoryResponse proto.InternalMessageInfo
+
+func (m *CartHistoryResponse) GetEntries() []*HistoryEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+func (m *CartHistoryResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// CartOperation is a single change of a Cart applied as a part of a batch.
+type CartOperation struct {
+	Type      CartOperation_Type `protobuf:"varint,1,opt,name=type,proto3,enum=cooldryplace.protobuf.CartOperation_Type" json:"type,omitempty"`
+	ProductId int64              `protobuf:"varint,2,opt,name=productId,proto3" json:"productId,omitempty"`
+	Quantity  uint32             `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// options identify the LineItem together with productId.
+	Options map[string]string `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// note is only used by ADD, it replaces the one of the LineItem unless it is empty.
+	Note                 string   `protobuf:"bytes,5,opt,name=note,proto3" json:"note,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CartOperation) Reset()         { *m = CartOperation{} }
+func (m *CartOperation) String() string { return proto.CompactTextString(m) }
+func (*CartOperation) ProtoMessage()    {}
+func (*CartOperation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{21}
+}
+
+func (m *CartOperation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CartOperation.Unmarshal(m, b)
+}
+func (m *CartOperation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CartOperation.Marshal(b, m, deterministic)
+}
+func (m *CartOperation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CartOperation.Merge(m, src)
+}
+func (m *CartOperation) XXX_Size() int {
+	return xxx_messageInfo_CartOperation.Size(m)
+}
+func (m *CartOperation) XXX_DiscardUnknown() {
+	xxx_messageInfo_CartOperation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CartOperation proto.InternalMessageInfo
+
+func (m *CartOperation) GetType() CartOperation_Type {
+	if m != nil {
+		return m.Type
+	}
+	return CartOperation_ADD
+}
+
+func (m *CartOperation) GetProductId() int64 {
+	if m != nil {
+		return m.ProductId
+	}
+	return 0
+}
+
+func (m *CartOperation) GetQuantity() uint32 {
+	if m != nil {
+		return m.Quantity
+	}
+	return 0
+}
+
+func (m *CartOperation) GetOptions() map[string]string {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *CartOperation) GetNote() string {
+	if m != nil {
+		return m.Note
+	}
+	return ""
+}
+
+// ApplyCartOperationsRequest provides a batch of changes of a Cart.
+type ApplyCartOperationsRequest struct {
+	CartId     int64            `protobuf:"varint,1,opt,name=cartId,proto3" json:"cartId,omitempty"`
+	Operations []*CartOperation `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	// expectedVersion if set makes the request fail unless the Cart has this version before the first operation.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	// idempotencyKey makes retries of the request return the original result without applying the changes again.
+	// It can also be provided with "idempotency-key" gRPC metadata. Results of batches that are not committed
+	// are not kept, so a corrected retry with the same key is applied.
+	IdempotencyKey       string   `protobuf:"bytes,4,opt,name=idempotencyKey,proto3" json:"idempotencyKey,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ApplyCartOperationsRequest) Reset()         { *m = ApplyCartOperationsRequest{} }
+func (m *ApplyCartOperationsRequest) String() string { return proto.CompactTextString(m) }
+func (*ApplyCartOperationsRequest) ProtoMessage()    {}
+func (*ApplyCartOperationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{22}
+}
+
+func (m *ApplyCartOperationsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApplyCartOperationsRequest.Unmarshal(m, b)
+}
+func (m *ApplyCartOperationsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApplyCartOperationsRequest.Marshal(b, m, deterministic)
+}
+func (m *ApplyCartOperationsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApplyCartOperationsRequest.Merge(m, src)
+}
+func (m *ApplyCartOperationsRequest) XXX_Size() int {
+	return xxx_messageInfo_ApplyCartOperationsRequest.Size(m)
+}
+func (m *ApplyCartOperationsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApplyCartOperationsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApplyCartOperationsRequest proto.InternalMessageInfo
+
+func (m *ApplyCartOperationsRequest) GetCartId() int64 {
+	if m != nil {
+		return m.CartId
+	}
+	return 0
+}
+
+func (m *ApplyCartOperationsRequest) GetOperations() []*CartOperation {
+	if m != nil {
+		return m.Operations
+	}
+	return nil
+}
+
+func (m *ApplyCartOperationsRequest) GetExpectedVersion() int64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+func (m *ApplyCartOperationsRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+// OperationResult describes the outcome of a CartOperation. quantity of the Product is the one after the operation.
+// code is a gRPC status code of the operation that failed the batch, operations after it are skipped.
+type OperationResult struct {
+	Quantity             uint32   `protobuf:"varint,1,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Code                 int32    `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Skipped              bool     `protobuf:"varint,4,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OperationResult) Reset()         { *m = OperationResult{} }
+func (m *OperationResult) String() string { return proto.CompactTextString(m) }
+func (*OperationResult) ProtoMessage()    {}
+func (*OperationResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{23}
+}
+
+func (m *OperationResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OperationResult.Unmarshal(m, b)
+}
+func (m *OperationResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OperationResult.Marshal(b, m, deterministic)
+}
+func (m *OperationResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OperationResult.Merge(m, src)
+}
+func (m *OperationResult) XXX_Size() int {
+	return xxx_messageInfo_OperationResult.Size(m)
+}
+func (m *OperationResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_OperationResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OperationResult proto.InternalMessageInfo
+
+func (m *OperationResult) GetQuantity() uint32 {
+	if m != nil {
+		return m.Quantity
+	}
+	return 0
+}
+
+func (m *OperationResult) GetCode() int32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *OperationResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *OperationResult) GetSkipped() bool {
+	if m != nil {
+		return m.Skipped
+	}
+	return false
+}
+
+// ApplyCartOperationsResponse contains results of every operation in the order of the request.
+// cart is the resulting Cart when the operations are committed.
+type ApplyCartOperationsResponse struct {
+	Committed            bool               `protobuf:"varint,1,opt,name=committed,proto3" json:"committed,omitempty"`
+	Results              []*OperationResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	Cart                 *Cart              `protobuf:"bytes,3,opt,name=cart,proto3" json:"cart,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ApplyCartOperationsResponse) Reset()         { *m = ApplyCartOperationsResponse{} }
+func (m *ApplyCartOperationsResponse) String() string { return proto.CompactTextString(m) }
+func (*ApplyCartOperationsResponse) ProtoMessage()    {}
+func (*ApplyCartOperationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{24}
+}
+
+func (m *ApplyCartOperationsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApplyCartOperationsResponse.Unmarshal(m, b)
+}
+func (m *ApplyCartOperationsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApplyCartOperationsResponse.Marshal(b, m, deterministic)
+}
+func (m *ApplyCartOperationsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApplyCartOperationsResponse.Merge(m, src)
+}
+func (m *ApplyCartOperationsResponse) XXX_Size() int {
+	return xxx_messageInfo_ApplyCartOperationsResponse.Size(m)
+}
+func (m *ApplyCartOperationsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApplyCartOperationsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApplyCartOperationsResponse proto.InternalMessageInfo
+
+func (m *ApplyCartOperationsResponse) GetCommitted() bool {
+	if m != nil {
+		return m.Committed
+	}
+	return false
+}
+
+func (m *ApplyCartOperationsResponse) GetResults() []*OperationResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+func (m *ApplyCartOperationsResponse) GetCart() *Cart {
+	if m != nil {
+		return m.Cart
+	}
+	return nil
+}
+
+// Snapshot is an immutable copy of a Cart. hash identifies the content: LineItems, coupons and prices.
+type Snapshot struct {
+	Id                   int64                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Cart                 *Cart                `protobuf:"bytes,2,opt,name=cart,proto3" json:"cart,omitempty"`
+	Hash                 string               `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Snapshot) Reset()         { *m = Snapshot{} }
+func (m *Snapshot) String() string { return proto.CompactTextString(m) }
+func (*Snapshot) ProtoMessage()    {}
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{25}
+}
+
+func (m *Snapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Snapshot.Unmarshal(m, b)
+}
+func (m *Snapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Snapshot.Marshal(b, m, deterministic)
+}
+func (m *Snapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Snapshot.Merge(m, src)
+}
+func (m *Snapshot) XXX_Size() int {
+	return xxx_messageInfo_Snapshot.Size(m)
+}
+func (m *Snapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_Snapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Snapshot proto.InternalMessageInfo
+
+func (m *Snapshot) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Snapshot) GetCart() *Cart {
+	if m != nil {
+		return m.Cart
+	}
+	return nil
+}
+
+func (m *Snapshot) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *Snapshot) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+// SnapshotRequest is used to fetch a Snapshot.
+type SnapshotRequest struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotRequest) Reset()         { *m = SnapshotRequest{} }
+func (m *SnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotRequest) ProtoMessage()    {}
+func (*SnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{26}
+}
+
+func (m *SnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotRequest.Unmarshal(m, b)
+}
+func (m *SnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *SnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotRequest.Merge(m, src)
+}
+func (m *SnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotRequest.Size(m)
+}
+func (m *SnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotRequest proto.InternalMessageInfo
+
+func (m *SnapshotRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+// CloneCartRequest copies LineItems of the Cart with cartId or of the Snapshot with snapshotId, exactly one of them must be set.
+type CloneCartRequest struct {
+	CartId     int64 `protobuf:"varint,1,opt,name=cartId,proto3" json:"cartId,omitempty"`
+	SnapshotId int64 `protobuf:"varint,2,opt,name=snapshotId,proto3" json:"snapshotId,omitempty"`
+	UserId     int64 `protobuf:"varint,3,opt,name=userId,proto3" json:"userId,omitempty"`
+	// skipUnavailable drops LineItems of Products that can not be ordered anymore instead of failing the request.
+	SkipUnavailable bool `protobuf:"varint,4,opt,name=skipUnavailable,proto3" json:"skipUnavailable,omitempty"`
+	// idempotencyKey makes retries of the request return the original result without creating another Cart.
+	// It can also be provided with "idempotency-key" gRPC metadata.
+	IdempotencyKey       string   `protobuf:"bytes,5,opt,name=idempotencyKey,proto3" json:"idempotencyKey,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CloneCartRequest) Reset()         { *m = CloneCartRequest{} }
+func (m *CloneCartRequest) String() string { return proto.CompactTextString(m) }
+func (*CloneCartRequest) ProtoMessage()    {}
+func (*CloneCartRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{27}
+}
+
+func (m *CloneCartRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CloneCartRequest.Unmarshal(m, b)
+}
+func (m *CloneCartRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CloneCartRequest.Marshal(b, m, deterministic)
+}
+func (m *CloneCartRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CloneCartRequest.Merge(m, src)
+}
+func (m *CloneCartRequest) XXX_Size() int {
+	return xxx_messageInfo_CloneCartRequest.Size(m)
+}
+func (m *CloneCartRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CloneCartRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CloneCartRequest proto.InternalMessageInfo
+
+func (m *CloneCartRequest) GetCartId() int64 {
+	if m != nil {
+		return m.CartId
+	}
+	return 0
+}
+
+func (m *CloneCartRequest) GetSnapshotId() int64 {
+	if m != nil {
+		return m.SnapshotId
+	}
+	return 0
+}
+
+func (m *CloneCartRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *CloneCartRequest) GetSkipUnavailable() bool {
+	if m != nil {
+		return m.SkipUnavailable
+	}
+	return false
+}
+
+func (m *CloneCartRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+// CloneCartResponse contains the new Cart and LineItems dropped because their Products are unavailable.
+// When only a part of the quantity is available, skipped LineItem has the quantity that was not cloned.
+type CloneCartResponse struct {
+	Cart                 *Cart       `protobuf:"bytes,1,opt,name=cart,proto3" json:"cart,omitempty"`
+	Skipped              []*LineItem `protobuf:"bytes,2,rep,name=skipped,proto3" json:"skipped,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *CloneCartResponse) Reset()         { *m = CloneCartResponse{} }
+func (m *CloneCartResponse) String() string { return proto.CompactTextString(m) }
+func (*CloneCartResponse) ProtoMessage()    {}
+func (*CloneCartResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{28}
+}
+
+func (m *CloneCartResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CloneCartResponse.Unmarshal(m, b)
+}
+func (m *CloneCartResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CloneCartResponse.Marshal(b, m, deterministic)
+}
+func (m *CloneCartResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CloneCartResponse.Merge(m, src)
+}
+func (m *CloneCartResponse) XXX_Size() int {
+	return xxx_messageInfo_CloneCartResponse.Size(m)
+}
+func (m *CloneCartResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CloneCartResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CloneCartResponse proto.InternalMessageInfo
+
+func (m *CloneCartResponse) GetCart() *Cart {
+	if m != nil {
+		return m.Cart
+	}
+	return nil
+}
+
+func (m *CloneCartResponse) GetSkipped() []*LineItem {
+	if m != nil {
+		return m.Skipped
+	}
+	return nil
+}
+
+// CartMember is a User sharing the Cart.
+type CartMember struct {
+	UserId               int64                `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	Role                 MemberRole           `protobuf:"varint,2,opt,name=role,proto3,enum=cooldryplace.protobuf.MemberRole" json:"role,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *CartMember) Reset()         { *m = CartMember{} }
+func (m *CartMember) String() string { return proto.CompactTextString(m) }
+func (*CartMember) ProtoMessage()    {}
+func (*CartMember) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{29}
+}
+
+func (m *CartMember) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CartMember.Unmarshal(m, b)
+}
+func (m *CartMember) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CartMember.Marshal(b, m, deterministic)
+}
+func (m *CartMember) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CartMember.Merge(m, src)
+}
+func (m *CartMember) XXX_Size() int {
+	return xxx_messageInfo_CartMember.Size(m)
+}
+func (m *CartMember) XXX_DiscardUnknown() {
+	xxx_messageInfo_CartMember.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CartMember proto.InternalMessageInfo
+
+func (m *CartMember) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *CartMember) GetRole() MemberRole {
+	if m != nil {
+		return m.Role
+	}
+	return MemberRole_MEMBER_ROLE_UNSPECIFIED
+}
+
+func (m *CartMember) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+// InviteMemberRequest is used to add a User to the Cart or to change the role of a member.
+type InviteMemberRequest struct {
+	CartId               int64      `protobuf:"varint,1,opt,name=cartId,proto3" json:"cartId,omitempty"`
+	UserId               int64      `protobuf:"varint,2,opt,name=userId,proto3" json:"userId,omitempty"`
+	Role                 MemberRole `protobuf:"varint,3,opt,name=role,proto3,enum=cooldryplace.protobuf.MemberRole" json:"role,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *InviteMemberRequest) Reset()         { *m = InviteMemberRequest{} }
+func (m *InviteMemberRequest) String() string { return proto.CompactTextString(m) }
+func (*InviteMemberRequest) ProtoMessage()    {}
+func (*InviteMemberRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{30}
+}
+
+func (m *InviteMemberRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InviteMemberRequest.Unmarshal(m, b)
+}
+func (m *InviteMemberRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InviteMemberRequest.Marshal(b, m, deterministic)
+}
+func (m *InviteMemberRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InviteMemberRequest.Merge(m, src)
+}
+func (m *InviteMemberRequest) XXX_Size() int {
+	return xxx_messageInfo_InviteMemberRequest.Size(m)
+}
+func (m *InviteMemberRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InviteMemberRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InviteMemberRequest proto.InternalMessageInfo
+
+func (m *InviteMemberRequest) GetCartId() int64 {
+	if m != nil {
+		return m.CartId
+	}
+	return 0
+}
+
+func (m *InviteMemberRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *InviteMemberRequest) GetRole() MemberRole {
+	if m != nil {
+		return m.Role
+	}
+	return MemberRole_MEMBER_ROLE_UNSPECIFIED
+}
+
+// RemoveMemberRequest is used to remove a User from the Cart.
+type RemoveMemberRequest struct {
+	CartId               int64    `protobuf:"varint,1,opt,name=cartId,proto3" json:"cartId,omitempty"`
+	UserId               int64    `protobuf:"varint,2,opt,name=userId,proto3" json:"userId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveMemberRequest) Reset()         { *m = RemoveMemberRequest{} }
+func (m *RemoveMemberRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveMemberRequest) ProtoMessage()    {}
+func (*RemoveMemberRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{31}
+}
+
+func (m *RemoveMemberRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveMemberRequest.Unmarshal(m, b)
+}
+func (m *RemoveMemberRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveMemberRequest.Marshal(b, m, deterministic)
+}
+func (m *RemoveMemberRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveMemberRequest.Merge(m, src)
+}
+func (m *RemoveMemberRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveMemberRequest.Size(m)
+}
+func (m *RemoveMemberRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveMemberRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveMemberRequest proto.InternalMessageInfo
+
+func (m *RemoveMemberRequest) GetCartId() int64 {
+	if m != nil {
+		return m.CartId
+	}
+	return 0
+}
+
+func (m *RemoveMemberRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+// ListMembersResponse contains members of the Cart.
+type ListMembersResponse struct {
+	Members              []*CartMember `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListMembersResponse) Reset()         { *m = ListMembersResponse{} }
+func (m *ListMembersResponse) String() string { return proto.CompactTextString(m) }
+func (*ListMembersResponse) ProtoMessage()    {}
+func (*ListMembersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c9a99120c5507bc1, []int{32}
+}
+
+func (m *ListMembersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListMembersResponse.Unmarshal(m, b)
+}
+func (m *ListMembersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListMembersResponse.Marshal(b, m, deterministic)
+}
+func (m *ListMembersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListMembersResponse.Merge(m, src)
+}
+func (m *ListMembersResponse) XXX_Size() int {
+	return xxx_messageInfo_ListMembersResponse.Size(m)
+}
+func (m *ListMembersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListMembersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListMembersResponse proto.InternalMessageInfo
+
+func (m *ListMembersResponse) GetMembers() []*CartMember {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("cooldryplace.protobuf.CartStatus", CartStatus_name, CartStatus_value)
+	proto.RegisterEnum("cooldryplace.protobuf.MergeStrategy", MergeStrategy_name, MergeStrategy_value)
+	proto.RegisterEnum("cooldryplace.protobuf.MemberRole", MemberRole_name, MemberRole_value)
+	proto.RegisterEnum("cooldryplace.protobuf.CartOperation_Type", CartOperation_Type_name, CartOperation_Type_value)
+	proto.RegisterType((*LineItem)(nil), "cooldryplace.protobuf.LineItem")
+	proto.RegisterMapType((map[string]string)(nil), "cooldryplace.protobuf.LineItem.OptionsEntry")
+	proto.RegisterType((*Cart)(nil), "cooldryplace.protobuf.Cart")
+	proto.RegisterType((*Discount)(nil), "cooldryplace.protobuf.Discount")
+	proto.RegisterType((*CartCreateRequest)(nil), "cooldryplace.protobuf.CartCreateRequest")
+	proto.RegisterType((*CartRequest)(nil), "cooldryplace.protobuf.CartRequest")
+	proto.RegisterType((*CartResponse)(nil), "cooldryplace.protobuf.CartResponse")
+	proto.RegisterType((*CartDeleteRequest)(nil), "cooldryplace.protobuf.CartDeleteRequest")
+	proto.RegisterType((*AddProductRequest)(nil), "cooldryplace.protobuf.AddProductRequest")
+	proto.RegisterMapType((map[string]string)(nil), "cooldryplace.protobuf.AddProductRequest.OptionsEntry")
+	proto.RegisterType((*DelProductRequest)(nil), "cooldryplace.protobuf.DelProductRequest")
+	proto.RegisterMapType((map[string]string)(nil), "cooldryplace.protobuf.DelProductRequest.OptionsEntry")
+	proto.RegisterType((*EmptyCartRequest)(nil), "cooldryplace.protobuf.EmptyCartRequest")
+	proto.RegisterType((*SetProductQuantityRequest)(nil), "cooldryplace.protobuf.SetProductQuantityRequest")
+	proto.RegisterMapType((map[string]string)(nil), "cooldryplace.protobuf.SetProductQuantityRequest.OptionsEntry")
+	proto.RegisterType((*RemoveProductUnitsRequest)(nil), "cooldryplace.protobuf.RemoveProductUnitsRequest")
+	proto.RegisterMapType((map[string]string)(nil), "cooldryplace.protobuf.RemoveProductUnitsRequest.OptionsEntry")
+	proto.RegisterType((*ListCartsRequest)(nil), "cooldryplace.protobuf.ListCartsRequest")
+	proto.RegisterType((*ListCartsResponse)(nil), "cooldryplace.protobuf.ListCartsResponse")
+	proto.RegisterType((*ActiveCartRequest)(nil), "cooldryplace.protobuf.ActiveCartRequest")
+	proto.RegisterType((*MergeCartsRequest)(nil), "cooldryplace.protobuf.MergeCartsRequest")
+	proto.RegisterType((*SavedItemRequest)(nil), "cooldryplace.protobuf.SavedItemRequest")
+	proto.RegisterMapType((map[string]string)(nil), "cooldryplace.protobuf.SavedItemRequest.OptionsEntry")
+	proto.RegisterType((*CouponRequest)(nil), "cooldryplace.protobuf.CouponRequest")
+	proto.RegisterType((*CartHistoryRequest)(nil), "cooldryplace.protobuf.CartHistoryRequest")
+	proto.RegisterType((*HistoryEntry)(nil), "cooldryplace.protobuf.HistoryEntry")
+	proto.RegisterType((*CartHistoryResponse)(nil), "cooldryplace.protobuf.CartHistoryResponse")
+	proto.RegisterType((*CartOperation)(nil), "cooldryplace.protobuf.CartOperation")
+	proto.RegisterMapType((map[string]string)(nil), "cooldryplace.protobuf.CartOperation.OptionsEntry")
+	proto.RegisterType((*ApplyCartOperationsRequest)(nil), "cooldryplace.protobuf.ApplyCartOperationsRequest")
+	proto.RegisterType((*OperationResult)(nil), "cooldryplace.protobuf.OperationResult")
+	proto.RegisterType((*ApplyCartOperationsResponse)(nil), "cooldryplace.protobuf.ApplyCartOperationsResponse")
+	proto.RegisterType((*Snapshot)(nil), "cooldryplace.protobuf.Snapshot")
+	proto.RegisterType((*SnapshotRequest)(nil), "cooldryplace.protobuf.SnapshotRequest")
+	proto.RegisterType((*CloneCartRequest)(nil), "cooldryplace.protobuf.CloneCartRequest")
+	proto.RegisterType((*CloneCartResponse)(nil), "cooldryplace.protobuf.CloneCartResponse")
+	proto.RegisterType((*CartMember)(nil), "cooldryplace.protobuf.CartMember")
+	proto.RegisterType((*InviteMemberRequest)(nil), "cooldryplace.protobuf.InviteMemberRequest")
+	proto.RegisterType((*RemoveMemberRequest)(nil), "cooldryplace.protobuf.RemoveMemberRequest")
+	proto.RegisterType((*ListMembersResponse)(nil), "cooldryplace.protobuf.ListMembersResponse")
+}
+
+func init() { proto.RegisterFile("cart_service.proto", fileDescriptor_c9a99120c5507bc1) }
+
+var fileDescriptor_c9a99120c5507bc1 = []byte{
+	// 2151 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x5a, 0x4f, 0x6f, 0x1b, 0xc7,
+	0x15, 0xcf, 0x2e, 0x49, 0x49, 0x7c, 0x14, 0x25, 0x6a, 0xe4, 0x38, 0x2c, 0x9d, 0x36, 0xca, 0xda,
+	0x70, 0x59, 0xb7, 0x90, 0x12, 0xb5, 0x06, 0x92, 0x14, 0x46, 0x42, 0x93, 0x2b, 0x85, 0xb0, 0x28,
+	0xaa, 0x43, 0x4a, 0x76, 0x92, 0xc6, 0xca, 0x6a, 0x77, 0x42, 0x2d, 0x4c, 0xee, 0xae, 0x77, 0x87,
+	0x4c, 0x18, 0x18, 0xe8, 0xa7, 0xe8, 0xa1, 0x28, 0xd0, 0x73, 0x0f, 0x45, 0x4f, 0x3d, 0x16, 0xbd,
+	0xf6, 0xd2, 0x73, 0xd1, 0x4f, 0x53, 0x14, 0x33, 0xfb, 0x97, 0x2b, 0x2e, 0x77, 0xa5, 0xd0, 0x45,
+	0x7b, 0x32, 0xe7, 0xed, 0x7b, 0x6f, 0x66, 0x7e, 0xef, 0xcf, 0xbc, 0xf7, 0x2c, 0x40, 0xaa, 0x62,
+	0xd3, 0x73, 0x87, 0xd8, 0x13, 0x5d, 0x25, 0xbb, 0x96, 0x6d, 0x52, 0x13, 0xbd, 0xa9, 0x9a, 0xe6,
+	0x50, 0xb3, 0xa7, 0xd6, 0x50, 0xf1, 0x69, 0x17, 0xe3, 0xaf, 0x6b, 0x77, 0x06, 0xa6, 0x39, 0x18,
+	0x92, 0x3d, 0x9f, 0xb0, 0x47, 0x46, 0x16, 0x9d, 0xba, 0xdf, 0x6b, 0xef, 0xc4, 0x3f, 0x52, 0x7d,
+	0x44, 0x1c, 0xaa, 0x8c, 0x2c, 0x97, 0x41, 0xfa, 0x9b, 0x08, 0x6b, 0x47, 0xba, 0x41, 0xda, 0x94,
+	0x8c, 0xd0, 0xdb, 0x50, 0xb4, 0x6c, 0x53, 0x1b, 0xab, 0xb4, 0xad, 0x55, 0x85, 0x1d, 0xa1, 0x9e,
+	0xc3, 0x21, 0x01, 0xd5, 0x60, 0xed, 0xe5, 0x58, 0x31, 0xa8, 0x4e, 0xa7, 0x55, 0x71, 0x47, 0xa8,
+	0x97, 0x71, 0xb0, 0x66, 0x92, 0x63, 0x43, 0xa7, 0x27, 0xb6, 0xae, 0x92, 0x6a, 0xce, 0x95, 0x0c,
+	0x08, 0xec, 0xeb, 0x50, 0x37, 0x48, 0xdf, 0xa4, 0xca, 0xb0, 0x9a, 0x77, 0xbf, 0x06, 0x04, 0x74,
+	0x0f, 0xca, 0x36, 0x61, 0x57, 0x55, 0xa8, 0x6e, 0x1a, 0x6d, 0xad, 0x5a, 0xd8, 0x11, 0xea, 0x45,
+	0x3c, 0x4b, 0x44, 0x07, 0xb0, 0x6a, 0x5a, 0xec, 0xb7, 0x53, 0x5d, 0xd9, 0xc9, 0xd5, 0x4b, 0xfb,
+	0x3f, 0xdb, 0x9d, 0x8b, 0xc7, 0xae, 0x7f, 0x9b, 0xdd, 0xae, 0xcb, 0x2e, 0x1b, 0xd4, 0x9e, 0x62,
+	0x5f, 0x18, 0x21, 0xc8, 0x1b, 0x26, 0x25, 0xd5, 0x55, 0xbe, 0x09, 0xff, 0x5d, 0xfb, 0x08, 0xd6,
+	0xa3, 0xcc, 0xa8, 0x02, 0xb9, 0x17, 0x64, 0xca, 0x11, 0x28, 0x62, 0xf6, 0x13, 0xdd, 0x82, 0xc2,
+	0x44, 0x19, 0x8e, 0x09, 0xbf, 0x78, 0x11, 0xbb, 0x8b, 0x8f, 0xc4, 0x0f, 0x04, 0xe9, 0xaf, 0x79,
+	0xc8, 0x37, 0x15, 0x9b, 0xa2, 0x0d, 0x10, 0x75, 0x1f, 0x35, 0x51, 0xd7, 0xd0, 0x6d, 0x58, 0x19,
+	0x3b, 0xc4, 0x6e, 0x6b, 0x5c, 0x26, 0x87, 0xbd, 0x15, 0xfa, 0x00, 0x8a, 0xaa, 0x4d, 0x14, 0x4a,
+	0xb4, 0x06, 0xe5, 0x50, 0x95, 0xf6, 0x6b, 0xbb, 0xae, 0x99, 0xc2, 0x4b, 0xf4, 0x7d, 0x33, 0xe1,
+	0x90, 0x99, 0x49, 0x8e, 0x2d, 0xcd, 0x93, 0xcc, 0xa7, 0x4b, 0x06, 0xcc, 0xe8, 0x21, 0x14, 0x74,
+	0x4a, 0x46, 0x4e, 0xb5, 0xc0, 0xa1, 0x7b, 0x27, 0x05, 0x3a, 0xec, 0x72, 0xa3, 0x0f, 0x61, 0xc5,
+	0xa1, 0x0a, 0x1d, 0x33, 0xc8, 0x85, 0xfa, 0xc6, 0xfe, 0xbb, 0x09, 0x72, 0xec, 0xfe, 0x3d, 0xce,
+	0x88, 0x3d, 0x01, 0x54, 0x85, 0xd5, 0x09, 0xb1, 0x1d, 0xdd, 0x34, 0x38, 0xd2, 0x39, 0xec, 0x2f,
+	0x99, 0x1b, 0x39, 0xe3, 0x0b, 0xca, 0x7d, 0x61, 0x8d, 0x7f, 0x0a, 0xd6, 0xec, 0x9b, 0x3a, 0xb6,
+	0x6d, 0x62, 0xa8, 0xd3, 0x6a, 0x91, 0x23, 0x1d, 0xac, 0x99, 0x46, 0xd5, 0x1c, 0x5b, 0xcc, 0x01,
+	0x60, 0x27, 0x57, 0x2f, 0x62, 0x7f, 0x89, 0x1e, 0x41, 0x51, 0xd3, 0x1d, 0xd5, 0x1c, 0x1b, 0xd4,
+	0xa9, 0x96, 0x16, 0xde, 0xb0, 0xe5, 0xf1, 0xe1, 0x50, 0x82, 0xf9, 0x9f, 0xbf, 0x70, 0x3d, 0x74,
+	0x9d, 0x9f, 0x6a, 0x96, 0xc8, 0x3c, 0xc0, 0x3d, 0x73, 0x99, 0x7f, 0x75, 0x17, 0xe8, 0x63, 0x00,
+	0x47, 0x99, 0x10, 0xad, 0xcd, 0xd1, 0xdd, 0xc8, 0x86, 0x6e, 0x44, 0x44, 0x9a, 0xc0, 0x9a, 0x7f,
+	0x26, 0xe6, 0x9a, 0xaa, 0xa9, 0x11, 0xcf, 0xef, 0xf8, 0xef, 0xd9, 0x90, 0x14, 0xe3, 0x21, 0xb9,
+	0x03, 0x25, 0x8d, 0x38, 0xaa, 0xad, 0x73, 0xef, 0xe5, 0xde, 0x54, 0xc4, 0x51, 0x12, 0xf3, 0x42,
+	0x65, 0xc4, 0xb4, 0x7b, 0x71, 0xe7, 0xad, 0xa4, 0x1e, 0x6c, 0x31, 0xab, 0x35, 0xb9, 0x73, 0x61,
+	0xf2, 0x72, 0x4c, 0x1c, 0x1a, 0x71, 0x59, 0x61, 0xc6, 0x65, 0xef, 0xc3, 0x86, 0xae, 0x91, 0x91,
+	0x65, 0x52, 0x66, 0x89, 0x27, 0x64, 0xea, 0x85, 0x41, 0x8c, 0x2a, 0xfd, 0x10, 0x4a, 0x4c, 0xa9,
+	0xaf, 0x2e, 0x16, 0x11, 0xd2, 0xc7, 0xb0, 0xee, 0x7e, 0x76, 0x98, 0xd9, 0x08, 0xda, 0x83, 0x3c,
+	0x4b, 0x73, 0x9c, 0xa3, 0xb4, 0x7f, 0x67, 0x81, 0x73, 0x61, 0xce, 0x28, 0xdd, 0x75, 0x0f, 0xdd,
+	0x22, 0x43, 0x42, 0x49, 0xd2, 0x2e, 0xff, 0x12, 0x61, 0xab, 0xa1, 0x69, 0x27, 0x2e, 0x48, 0x3e,
+	0xd7, 0xcd, 0x53, 0xdb, 0x6d, 0x58, 0x61, 0x9b, 0xb7, 0x35, 0x2f, 0xaf, 0x79, 0x2b, 0x54, 0x87,
+	0x4d, 0xf2, 0xad, 0x45, 0x54, 0x4a, 0xb4, 0x33, 0xcf, 0xd3, 0x5d, 0x88, 0xe3, 0xe4, 0x39, 0xf0,
+	0x15, 0xe6, 0xc1, 0x87, 0xba, 0xf1, 0x14, 0xf7, 0x30, 0x01, 0x92, 0x2b, 0xd7, 0xfb, 0x2f, 0xe5,
+	0xba, 0x3f, 0x8a, 0xb0, 0xd5, 0x22, 0xc3, 0x6b, 0x41, 0x1b, 0xc2, 0x27, 0xa6, 0xc1, 0x97, 0xcb,
+	0x0a, 0x5f, 0x3e, 0x0d, 0xbe, 0xc2, 0x42, 0xf8, 0xae, 0x5c, 0x61, 0x3e, 0x7c, 0xdf, 0x0b, 0xaa,
+	0x57, 0x50, 0x91, 0xd9, 0x3b, 0x1c, 0x8d, 0x87, 0x10, 0x0a, 0x21, 0x0d, 0x0a, 0x31, 0x2b, 0x14,
+	0xb9, 0xb9, 0x81, 0xf8, 0x27, 0x11, 0x7e, 0xd0, 0x23, 0xd4, 0xbb, 0xe5, 0xaf, 0x3c, 0x57, 0xfe,
+	0x5f, 0x88, 0x85, 0xa7, 0x71, 0x23, 0x3d, 0x4a, 0x30, 0x52, 0xe2, 0xf1, 0x5f, 0x83, 0xb1, 0x18,
+	0x5c, 0x98, 0x8c, 0xcc, 0x09, 0xf1, 0xb6, 0x3c, 0x35, 0x74, 0xea, 0xfc, 0x5f, 0xc1, 0x95, 0x78,
+	0xfc, 0xd7, 0x00, 0x97, 0x06, 0x95, 0x23, 0xdd, 0xa1, 0xcc, 0xb5, 0x9d, 0xb4, 0xa7, 0xa3, 0x06,
+	0x6b, 0x96, 0x32, 0x20, 0x3d, 0xfd, 0x3b, 0x57, 0x51, 0x01, 0x07, 0x6b, 0x0e, 0xac, 0x32, 0x20,
+	0x7d, 0xf3, 0x05, 0xf1, 0xdf, 0xae, 0x90, 0x20, 0x0d, 0x61, 0x2b, 0xb2, 0x8b, 0xf7, 0x64, 0xbc,
+	0x0f, 0x05, 0x86, 0xa1, 0x53, 0x15, 0x76, 0x72, 0x69, 0x6f, 0x86, 0xcb, 0xc9, 0x9e, 0x77, 0x83,
+	0x7c, 0x4b, 0x4f, 0x82, 0x9d, 0xdc, 0xfb, 0xcc, 0x12, 0xa5, 0x9f, 0xc2, 0x56, 0x43, 0xa5, 0xfa,
+	0x84, 0xc4, 0x02, 0x76, 0xde, 0xa5, 0xa4, 0xbf, 0x0b, 0xb0, 0xd5, 0x21, 0xf6, 0x80, 0xcc, 0x40,
+	0x20, 0xc1, 0xba, 0x63, 0x8e, 0x6d, 0x95, 0x53, 0x03, 0x99, 0x19, 0x1a, 0xe3, 0xa1, 0x8a, 0x3d,
+	0x20, 0xb4, 0x19, 0xcd, 0x89, 0x33, 0x34, 0xf4, 0x09, 0xac, 0x39, 0xd4, 0x56, 0x28, 0x19, 0xb8,
+	0xe1, 0xbd, 0xb1, 0x7f, 0x2f, 0xe1, 0x9a, 0xfc, 0x0c, 0x3d, 0x8f, 0x17, 0x07, 0x52, 0xd9, 0xfd,
+	0x4b, 0xfa, 0xb7, 0x00, 0x95, 0x9e, 0x5f, 0x8d, 0xa4, 0xe5, 0xa9, 0xc5, 0xb5, 0x48, 0xf6, 0x84,
+	0x7e, 0x1c, 0x3a, 0x75, 0x9e, 0x9b, 0xf1, 0x17, 0x49, 0x39, 0x20, 0x76, 0xb2, 0xd7, 0xe0, 0xcb,
+	0x04, 0xca, 0x4d, 0x5e, 0x46, 0xa6, 0x5d, 0xde, 0x2f, 0xce, 0xc4, 0x48, 0x71, 0x96, 0xf9, 0xca,
+	0xd2, 0xd7, 0x80, 0x98, 0x75, 0x3f, 0xd5, 0x1d, 0x6a, 0xda, 0xd3, 0xb4, 0xbd, 0x6e, 0x1e, 0x34,
+	0x7f, 0x10, 0x61, 0xdd, 0xdb, 0xc4, 0xc5, 0x62, 0x4e, 0x57, 0x32, 0xf7, 0x39, 0xbe, 0x05, 0x05,
+	0x45, 0xa5, 0xa6, 0xed, 0xa9, 0x74, 0x17, 0x6c, 0x33, 0xd3, 0x22, 0xb6, 0x42, 0x7d, 0x17, 0x2a,
+	0xe2, 0x90, 0x30, 0xeb, 0x0f, 0x85, 0x39, 0xb5, 0xa9, 0x39, 0xd4, 0xfc, 0xe4, 0xcd, 0x3b, 0x88,
+	0x32, 0x8e, 0x92, 0x18, 0x87, 0x41, 0xbe, 0x09, 0x38, 0x56, 0x5d, 0x8e, 0x08, 0x89, 0x9d, 0x56,
+	0x23, 0x54, 0xd1, 0xdd, 0x4e, 0xa1, 0x88, 0xbd, 0xd5, 0x6c, 0x0f, 0x55, 0xbc, 0x46, 0x0f, 0x25,
+	0x7d, 0x07, 0xdb, 0x33, 0x86, 0xf0, 0xf2, 0xca, 0x23, 0x58, 0x25, 0x06, 0xb5, 0x75, 0xe2, 0x67,
+	0x96, 0xbb, 0x09, 0x2e, 0x19, 0x05, 0x17, 0xfb, 0x32, 0x19, 0x73, 0xcc, 0x3f, 0x45, 0x28, 0xb3,
+	0xcd, 0xbb, 0x01, 0x82, 0x8f, 0x20, 0x4f, 0xa7, 0x96, 0x5b, 0xf1, 0x6f, 0xec, 0xff, 0x64, 0x41,
+	0x36, 0x0b, 0x64, 0x76, 0xfb, 0x53, 0x8b, 0x60, 0x2e, 0x96, 0x12, 0x90, 0xd1, 0x97, 0x29, 0x17,
+	0x7b, 0x99, 0x9e, 0xc4, 0x43, 0xf0, 0xfd, 0x4c, 0x7b, 0x2f, 0x2e, 0x33, 0x0b, 0x4b, 0x2a, 0x33,
+	0xf7, 0x20, 0xcf, 0x2e, 0x89, 0x56, 0x21, 0xd7, 0x68, 0xb5, 0x2a, 0x6f, 0xb0, 0x1f, 0x3d, 0xb9,
+	0x5f, 0x11, 0x10, 0xc0, 0x0a, 0x96, 0x3b, 0xdd, 0x33, 0xb9, 0x22, 0xa2, 0x22, 0x14, 0xe4, 0xce,
+	0x49, 0xff, 0xb3, 0x4a, 0x4e, 0xfa, 0x87, 0x00, 0xb5, 0x86, 0x65, 0x0d, 0xa7, 0x33, 0xa7, 0x75,
+	0xd2, 0xc2, 0xac, 0x05, 0x10, 0x38, 0xb3, 0x53, 0x15, 0x39, 0x0e, 0xf7, 0xb2, 0xe0, 0x80, 0x23,
+	0x72, 0xcb, 0x2f, 0x64, 0xa5, 0x97, 0xb0, 0x19, 0x6e, 0x45, 0x9c, 0xf1, 0x90, 0xce, 0xd8, 0x52,
+	0x88, 0xd9, 0x32, 0x9a, 0x99, 0x0a, 0x5e, 0x66, 0xba, 0x05, 0x05, 0x62, 0xdb, 0x61, 0x38, 0xf3,
+	0x05, 0x6b, 0xa1, 0x9d, 0x17, 0xba, 0x65, 0x11, 0x8d, 0xef, 0xbc, 0x86, 0xfd, 0xa5, 0xf4, 0x67,
+	0x01, 0xee, 0xcc, 0x45, 0xd0, 0x8b, 0x8f, 0xb7, 0xa1, 0xa8, 0x9a, 0xa3, 0x91, 0x4e, 0x29, 0x71,
+	0x51, 0x5c, 0xc3, 0x21, 0x01, 0x7d, 0x02, 0xab, 0x36, 0x3f, 0xa7, 0x8f, 0xe2, 0xfd, 0x04, 0x14,
+	0x63, 0xd7, 0xc2, 0xbe, 0x58, 0xd0, 0x0a, 0xe6, 0xb2, 0xb6, 0x82, 0xbf, 0x13, 0x60, 0xad, 0x67,
+	0x28, 0x96, 0x73, 0x69, 0x5e, 0x1d, 0xbd, 0xf8, 0xda, 0xc4, 0x8c, 0xda, 0x18, 0x84, 0x97, 0x8a,
+	0x73, 0xe9, 0xa1, 0xc5, 0x7f, 0xcf, 0xe6, 0x98, 0xfc, 0x75, 0x72, 0xcc, 0xbb, 0xb0, 0xe9, 0x1f,
+	0x2d, 0xa9, 0x49, 0xfd, 0x8b, 0x00, 0x95, 0xe6, 0xd0, 0x34, 0x48, 0x96, 0xfe, 0xe0, 0x47, 0x00,
+	0x8e, 0xa7, 0x2f, 0x88, 0xf3, 0x08, 0x25, 0x52, 0xa6, 0xe4, 0x66, 0x6a, 0xaf, 0x3a, 0x6c, 0x32,
+	0xfb, 0x9e, 0x1a, 0xca, 0x44, 0xd1, 0x87, 0xca, 0xc5, 0x90, 0x78, 0x66, 0x8f, 0x93, 0xb3, 0x76,
+	0xa8, 0xd2, 0x6f, 0x60, 0x2b, 0x72, 0xea, 0x1b, 0xb6, 0xf1, 0xe8, 0xc3, 0xd0, 0x0d, 0xc5, 0x6c,
+	0x13, 0x93, 0xc0, 0x4f, 0x7f, 0x2b, 0x00, 0x30, 0x4d, 0x1d, 0x32, 0xba, 0x20, 0x76, 0x62, 0xd5,
+	0xf9, 0x10, 0xf2, 0xb6, 0x39, 0x74, 0x43, 0x22, 0x79, 0x6c, 0xe5, 0x2a, 0xc1, 0xe6, 0x90, 0x60,
+	0xce, 0x7e, 0xf3, 0xd1, 0x9c, 0xf4, 0x0a, 0xb6, 0xdb, 0xc6, 0x44, 0xa7, 0xc4, 0xd3, 0x99, 0x62,
+	0xd1, 0xa4, 0xd9, 0xa0, 0x7f, 0xee, 0xdc, 0xb5, 0xce, 0x2d, 0xc9, 0xb0, 0xed, 0xd6, 0xff, 0xdf,
+	0x6b, 0x77, 0x09, 0xc3, 0x36, 0xab, 0xb8, 0x5d, 0x25, 0x61, 0xec, 0xff, 0x12, 0x56, 0x47, 0x2e,
+	0xc9, 0x7b, 0x1b, 0x17, 0x8d, 0x01, 0xbd, 0x13, 0xf8, 0x12, 0x0f, 0xbe, 0x02, 0x08, 0xa7, 0x83,
+	0x68, 0x13, 0x4a, 0xbd, 0x7e, 0xa3, 0x7f, 0xda, 0x3b, 0xef, 0x9e, 0xc8, 0xc7, 0x95, 0x37, 0xd0,
+	0x5b, 0xb0, 0xed, 0x11, 0x9a, 0x9f, 0xca, 0xcd, 0x27, 0xed, 0xe3, 0xc3, 0xf3, 0xee, 0x29, 0xcb,
+	0xf4, 0x08, 0x36, 0x7c, 0x4e, 0xdc, 0x92, 0xb1, 0xdc, 0xaa, 0x88, 0xe8, 0x16, 0x54, 0x3c, 0x5a,
+	0xe3, 0x71, 0xe3, 0xb8, 0xd5, 0x3d, 0x96, 0x5b, 0x95, 0xdc, 0x83, 0x03, 0x28, 0xcf, 0xd4, 0xc1,
+	0xa8, 0x0c, 0xc5, 0x8e, 0x8c, 0x0f, 0xe5, 0xf3, 0xde, 0x69, 0xa7, 0xf2, 0x46, 0xb8, 0xec, 0x34,
+	0x9e, 0x55, 0x04, 0xb6, 0xa3, 0xbb, 0x3c, 0xc1, 0xf2, 0x81, 0x8c, 0xcf, 0x7b, 0xdd, 0x53, 0xdc,
+	0x94, 0x2b, 0xe2, 0x03, 0x0b, 0x20, 0x04, 0x16, 0xdd, 0x81, 0xb7, 0x3a, 0x72, 0xe7, 0xb1, 0x8c,
+	0xcf, 0x71, 0xf7, 0x48, 0x3e, 0x3f, 0x3d, 0xee, 0x9d, 0xc8, 0xcd, 0xf6, 0x41, 0x5b, 0x66, 0xef,
+	0xd1, 0x6d, 0x40, 0xd1, 0x8f, 0x67, 0x6d, 0xf9, 0xa9, 0x8c, 0x2b, 0x42, 0x9c, 0x2e, 0xb7, 0xda,
+	0xfd, 0x2e, 0xae, 0x88, 0xe8, 0x4d, 0xd8, 0x8a, 0xd2, 0xbb, 0x4f, 0x8f, 0x65, 0x5c, 0xc9, 0xed,
+	0xff, 0xfe, 0x36, 0x14, 0x78, 0x07, 0x81, 0xbe, 0x00, 0x70, 0x27, 0x71, 0x6c, 0x89, 0xea, 0x0b,
+	0xf0, 0x9d, 0x19, 0xd8, 0xd5, 0xee, 0x2e, 0x0a, 0x36, 0xdf, 0x7e, 0x18, 0x56, 0x0f, 0xdd, 0xe6,
+	0x02, 0x49, 0x0b, 0xf9, 0xaf, 0xa1, 0xf3, 0x08, 0x8a, 0xc1, 0x78, 0x03, 0xfd, 0x38, 0x41, 0x22,
+	0x3e, 0x00, 0xa9, 0xdd, 0xbe, 0x12, 0x4c, 0x9c, 0x05, 0x1d, 0x03, 0xb8, 0x33, 0xbd, 0xd4, 0xeb,
+	0xcf, 0x8c, 0xfe, 0x12, 0xf5, 0x9d, 0x41, 0x09, 0x13, 0x87, 0x9a, 0x36, 0x59, 0xee, 0xad, 0x8f,
+	0x01, 0xc2, 0xd1, 0x5b, 0xe2, 0x39, 0xaf, 0x4c, 0xe7, 0x52, 0xee, 0x9d, 0xa6, 0xef, 0xca, 0xb8,
+	0x2a, 0x51, 0xdf, 0x73, 0x40, 0x57, 0xc7, 0x26, 0xe8, 0xbd, 0xeb, 0x4e, 0x58, 0x16, 0xe9, 0xbf,
+	0x3a, 0x67, 0x48, 0xd4, 0x9f, 0x38, 0x92, 0x58, 0xa0, 0xbf, 0x18, 0xb4, 0xfc, 0x89, 0x5e, 0x15,
+	0x1f, 0x3d, 0xd4, 0xea, 0xe9, 0x8c, 0x9e, 0xfd, 0x9e, 0x43, 0xf9, 0x90, 0xd0, 0xb0, 0xcf, 0x4f,
+	0x36, 0x61, 0x7c, 0x14, 0x90, 0xcd, 0x3f, 0xbe, 0x82, 0xcd, 0x43, 0x42, 0xbb, 0xf6, 0xeb, 0x8b,
+	0xe5, 0x2f, 0x00, 0xc2, 0xc1, 0x43, 0xa2, 0xf2, 0x2b, 0xb3, 0x89, 0x6c, 0xca, 0x9f, 0x41, 0xf9,
+	0x31, 0x19, 0xe8, 0x46, 0xf3, 0x92, 0xa8, 0x2f, 0xcc, 0xf1, 0x12, 0x03, 0xe7, 0x19, 0xeb, 0xb2,
+	0x47, 0x16, 0x8b, 0xdd, 0xae, 0xad, 0x11, 0x7b, 0x79, 0x9a, 0x4f, 0x01, 0x30, 0x31, 0x2d, 0x62,
+	0x2c, 0x37, 0xd2, 0xcf, 0xa0, 0xd4, 0xb8, 0x50, 0x0c, 0xcd, 0x5c, 0xb2, 0xde, 0x67, 0x50, 0x72,
+	0xcb, 0x6c, 0x3e, 0x73, 0x40, 0x89, 0xdd, 0x46, 0x74, 0x24, 0x91, 0x4d, 0xf3, 0x67, 0xb0, 0xee,
+	0x06, 0xdc, 0xf2, 0x55, 0xff, 0x1a, 0xb6, 0x30, 0x31, 0xc8, 0x37, 0x38, 0xfc, 0x0f, 0x59, 0x67,
+	0x79, 0x90, 0x0c, 0x60, 0xc3, 0x7b, 0x9e, 0xbc, 0xde, 0x1a, 0x2d, 0xea, 0x83, 0x67, 0x27, 0x28,
+	0xb5, 0x07, 0x59, 0x58, 0x83, 0x6b, 0xac, 0xb3, 0x81, 0xd2, 0x81, 0x69, 0x1f, 0x29, 0x94, 0xd8,
+	0x89, 0x09, 0x26, 0x3e, 0x75, 0xca, 0x76, 0x8d, 0xcf, 0x01, 0x3a, 0xe6, 0x84, 0xf4, 0xcd, 0x85,
+	0x4f, 0xe2, 0xcd, 0x74, 0xbf, 0x82, 0xed, 0x39, 0xcd, 0x19, 0x4a, 0xea, 0xd9, 0x93, 0x5b, 0xe1,
+	0xda, 0xfe, 0x75, 0x44, 0xbc, 0xdd, 0x7b, 0xb0, 0xee, 0xb7, 0x33, 0x99, 0x83, 0x21, 0xa9, 0xa2,
+	0xf7, 0x15, 0xb1, 0x00, 0x3b, 0x24, 0x34, 0x58, 0xde, 0x4f, 0xe1, 0xcf, 0xac, 0xf7, 0x39, 0x14,
+	0x83, 0x0e, 0x25, 0xd1, 0x0a, 0xf1, 0xce, 0xab, 0x56, 0x4f, 0x67, 0xf4, 0xc0, 0xf8, 0x12, 0xd6,
+	0xa3, 0x85, 0x3e, 0x4a, 0x72, 0xc0, 0x39, 0xdd, 0x40, 0x2d, 0xbd, 0x6e, 0x46, 0xd8, 0x8f, 0xe2,
+	0x14, 0xf5, 0x73, 0xca, 0xfd, 0xc4, 0x57, 0xf5, 0x4b, 0x28, 0x45, 0xca, 0xfa, 0x4c, 0xe6, 0x7b,
+	0xb0, 0xe0, 0x49, 0x8d, 0xb7, 0x07, 0x67, 0x50, 0x7c, 0xaa, 0x50, 0xf5, 0x72, 0xa9, 0x89, 0xf2,
+	0x3d, 0xe1, 0xf1, 0xbd, 0xcf, 0xa5, 0x81, 0x4e, 0x2f, 0xc7, 0x17, 0xbb, 0xaa, 0x39, 0xda, 0x8b,
+	0x8a, 0xec, 0xb1, 0x3e, 0xc6, 0xfb, 0x93, 0x96, 0x15, 0xfe, 0xcf, 0xcf, 0xff, 0x33, 0x00, 0x25,
+	0xfb, 0xdf, 0xb4, 0x31, 0x23, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// CartsClient is the client API for Carts service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CartsClient interface {
+	// CreateCart will create new Cart for User ID.
+	CreateCart(ctx context.Context, in *CartCreateRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	// GetCart returns current state of a Cart.
+	GetCart(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	// EmptyCart will remove all LineItems from the Cart. Saved items are kept.
+	EmptyCart(ctx context.Context, in *EmptyCartRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// DeleteCart with provided Cart ID. The Cart is soft-deleted and can be restored within the retention period.
+	DeleteCart(ctx context.Context, in *CartDeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// RestoreCart deleted within the retention period.
+	RestoreCart(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	// AddProduct to existing Cart.
+	AddProduct(ctx context.Context, in *AddProductRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// DelProduct from existing Cart.
+	DelProduct(ctx context.Context, in *DelProductRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// SetProductQuantity sets exact quantity of a Product in existing Cart.
+	SetProductQuantity(ctx context.Context, in *SetProductQuantityRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// RemoveProductUnits decreases quantity of a Product in existing Cart.
+	RemoveProductUnits(ctx context.Context, in *RemoveProductUnitsRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// ListCarts returns a page of User Carts, newest first.
+	ListCarts(ctx context.Context, in *ListCartsRequest, opts ...grpc.CallOption) (*ListCartsResponse, error)
+	// GetActiveCart returns the most recently updated Cart of a User.
+	GetActiveCart(ctx context.Context, in *ActiveCartRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	// GetOrCreateCart returns the active Cart of a User, a new Cart is created only when User has none.
+	GetOrCreateCart(ctx context.Context, in *CartCreateRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	// MergeCarts moves LineItems of the source Cart into the target Cart and deletes the source Cart.
+	// The source Cart can be restored with RestoreCart within the retention period.
+	// The caller must own the source Cart and be an editor of the target one.
+	MergeCarts(ctx context.Context, in *MergeCartsRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	// BeginCheckout freezes an open Cart while the Order is being placed.
+	BeginCheckout(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	// CompleteOrder marks the Cart being checked out as ordered.
+	CompleteOrder(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	// ReopenCart returns the Cart being checked out or abandoned to the open state.
+	ReopenCart(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	// AbandonCart marks the Cart User is not going to order.
+	AbandonCart(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	// ApplyCoupon applies a discount code to an open Cart.
+	ApplyCoupon(ctx context.Context, in *CouponRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	// RemoveCoupon removes a discount code from an open Cart.
+	RemoveCoupon(ctx context.Context, in *CouponRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	// RenewReservations extends stock reservations of the Cart being checked out.
+	RenewReservations(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	// GetCartHistory returns a page of the Cart changes, newest first.
+	GetCartHistory(ctx context.Context, in *CartHistoryRequest, opts ...grpc.CallOption) (*CartHistoryResponse, error)
+	// SaveForLater moves a Product from Cart items to its saved items, keeping quantity.
+	SaveForLater(ctx context.Context, in *SavedItemRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	// MoveToCart moves a saved Product back to Cart items, keeping quantity.
+	MoveToCart(ctx context.Context, in *SavedItemRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	// ApplyCartOperations applies a batch of changes to a Cart in order. Either all of them are committed or none.
+	ApplyCartOperations(ctx context.Context, in *ApplyCartOperationsRequest, opts ...grpc.CallOption) (*ApplyCartOperationsResponse, error)
+	// SnapshotCart freezes the current state of a Cart into an immutable Snapshot, e.g. when an order is placed.
+	SnapshotCart(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*Snapshot, error)
+	// GetSnapshot returns a Snapshot by ID.
+	GetSnapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (*Snapshot, error)
+	// CloneCart creates a new Cart of the User with LineItems of a past Cart or Snapshot, e.g. to buy them again.
+	// The caller must be a member of the source Cart.
+	CloneCart(ctx context.Context, in *CloneCartRequest, opts ...grpc.CallOption) (*CloneCartResponse, error)
+	// InviteMember adds a User to the shared Cart with a role or changes the role of an existing member.
+	InviteMember(ctx context.Context, in *InviteMemberRequest, opts ...grpc.CallOption) (*CartMember, error)
+	// RemoveMember from the shared Cart. The owner can not be removed.
+	RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// ListMembers returns members of the Cart, the owner first.
+	ListMembers(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*ListMembersResponse, error)
+	// WatchCart streams the current Cart and then the Cart again after every change.
+	// The stream ends when the Cart is deleted.
+	WatchCart(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (Carts_WatchCartClient, error)
+}
+
+type cartsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCartsClient(cc *grpc.ClientConn) CartsClient {
+	return &cartsClient{cc}
+}
+
+func (c *cartsClient) CreateCart(ctx context.Context, in *CartCreateRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/CreateCart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) GetCart(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/GetCart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) EmptyCart(ctx context.Context, in *EmptyCartRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/EmptyCart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) DeleteCart(ctx context.Context, in *CartDeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/DeleteCart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) RestoreCart(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/RestoreCart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) AddProduct(ctx context.Context, in *AddProductRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/AddProduct", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) DelProduct(ctx context.Context, in *DelProductRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/DelProduct", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) SetProductQuantity(ctx context.Context, in *SetProductQuantityRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/SetProductQuantity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) RemoveProductUnits(ctx context.Context, in *RemoveProductUnitsRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/RemoveProductUnits", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) ListCarts(ctx context.Context, in *ListCartsRequest, opts ...grpc.CallOption) (*ListCartsResponse, error) {
+	out := new(ListCartsResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/ListCarts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) GetActiveCart(ctx context.Context, in *ActiveCartRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/GetActiveCart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) GetOrCreateCart(ctx context.Context, in *CartCreateRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/GetOrCreateCart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) MergeCarts(ctx context.Context, in *MergeCartsRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/MergeCarts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) BeginCheckout(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/BeginCheckout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) CompleteOrder(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/CompleteOrder", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) ReopenCart(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/ReopenCart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) AbandonCart(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/AbandonCart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) ApplyCoupon(ctx context.Context, in *CouponRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/ApplyCoupon", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) RemoveCoupon(ctx context.Context, in *CouponRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/RemoveCoupon", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) RenewReservations(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/RenewReservations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) GetCartHistory(ctx context.Context, in *CartHistoryRequest, opts ...grpc.CallOption) (*CartHistoryResponse, error) {
+	out := new(CartHistoryResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/GetCartHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) SaveForLater(ctx context.Context, in *SavedItemRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/SaveForLater", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) MoveToCart(ctx context.Context, in *SavedItemRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/MoveToCart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) ApplyCartOperations(ctx context.Context, in *ApplyCartOperationsRequest, opts ...grpc.CallOption) (*ApplyCartOperationsResponse, error) {
+	out := new(ApplyCartOperationsResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/ApplyCartOperations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) SnapshotCart(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*Snapshot, error) {
+	out := new(Snapshot)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/SnapshotCart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) GetSnapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (*Snapshot, error) {
+	out := new(Snapshot)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/GetSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) CloneCart(ctx context.Context, in *CloneCartRequest, opts ...grpc.CallOption) (*CloneCartResponse, error) {
+	out := new(CloneCartResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/CloneCart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) InviteMember(ctx context.Context, in *InviteMemberRequest, opts ...grpc.CallOption) (*CartMember, error) {
+	out := new(CartMember)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/InviteMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/RemoveMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) ListMembers(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (*ListMembersResponse, error) {
+	out := new(ListMembersResponse)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Carts/ListMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartsClient) WatchCart(ctx context.Context, in *CartRequest, opts ...grpc.CallOption) (Carts_WatchCartClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Carts_serviceDesc.Streams[0], "/cooldryplace.protobuf.Carts/WatchCart", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cartsWatchCartClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Carts_WatchCartClient interface {
+	Recv() (*CartResponse, error)
+	grpc.ClientStream
+}
+
+type cartsWatchCartClient struct {
+	grpc.ClientStream
+}
+
+func (x *cartsWatchCartClient) Recv() (*CartResponse, error) {
+	m := new(CartResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// CartsServer is the server API for Carts service.
+type CartsServer interface {
+	// CreateCart will create new Cart for User ID.
+	CreateCart(context.Context, *CartCreateRequest) (*CartResponse, error)
+	// GetCart returns current state of a Cart.
+	GetCart(context.Context, *CartRequest) (*CartResponse, error)
+	// EmptyCart will remove all LineItems from the Cart. Saved items are kept.
+	EmptyCart(context.Context, *EmptyCartRequest) (*empty.Empty, error)
+	// DeleteCart with provided Cart ID. The Cart is soft-deleted and can be restored within the retention period.
+	DeleteCart(context.Context, *CartDeleteRequest) (*empty.Empty, error)
+	// RestoreCart deleted within the retention period.
+	RestoreCart(context.Context, *CartRequest) (*CartResponse, error)
+	// AddProduct to existing Cart.
+	AddProduct(context.Context, *AddProductRequest) (*empty.Empty, error)
+	// DelProduct from existing Cart.
+	DelProduct(context.Context, *DelProductRequest) (*empty.Empty, error)
+	// SetProductQuantity sets exact quantity of a Product in existing Cart.
+	SetProductQuantity(context.Context, *SetProductQuantityRequest) (*empty.Empty, error)
+	// RemoveProductUnits decreases quantity of a Product in existing Cart.
+	RemoveProductUnits(context.Context, *RemoveProductUnitsRequest) (*empty.Empty, error)
+	// ListCarts returns a page of User Carts, newest first.
+	ListCarts(context.Context, *ListCartsRequest) (*ListCartsResponse, error)
+	// GetActiveCart returns the most recently updated Cart of a User.
+	GetActiveCart(context.Context, *ActiveCartRequest) (*CartResponse, error)
+	// GetOrCreateCart returns the active Cart of a User, a new Cart is created only when User has none.
+	GetOrCreateCart(context.Context, *CartCreateRequest) (*CartResponse, error)
+	// MergeCarts moves LineItems of the source Cart into the target Cart and deletes the source Cart.
+	// The source Cart can be restored with RestoreCart within the retention period.
+	// The caller must own the source Cart and be an editor of the target one.
+	MergeCarts(context.Context, *MergeCartsRequest) (*CartResponse, error)
+	// BeginCheckout freezes an open Cart while the Order is being placed.
+	BeginCheckout(context.Context, *CartRequest) (*CartResponse, error)
+	// CompleteOrder marks the Cart being checked out as ordered.
+	CompleteOrder(context.Context, *CartRequest) (*CartResponse, error)
+	// ReopenCart returns the Cart being checked out or abandoned to the open state.
+	ReopenCart(context.Context, *CartRequest) (*CartResponse, error)
+	// AbandonCart marks the Cart User is not going to order.
+	AbandonCart(context.Context, *CartRequest) (*CartResponse, error)
+	// ApplyCoupon applies a discount code to an open Cart.
+	ApplyCoupon(context.Context, *CouponRequest) (*CartResponse, error)
+	// RemoveCoupon removes a discount code from an open Cart.
+	RemoveCoupon(context.Context, *CouponRequest) (*CartResponse, error)
+	// RenewReservations extends stock reservations of the Cart being checked out.
+	RenewReservations(context.Context, *CartRequest) (*CartResponse, error)
+	// GetCartHistory returns a page of the Cart changes, newest first.
+	GetCartHistory(context.Context, *CartHistoryRequest) (*CartHistoryResponse, error)
+	// SaveForLater moves a Product from Cart items to its saved items, keeping quantity.
+	SaveForLater(context.Context, *SavedItemRequest) (*CartResponse, error)
+	// MoveToCart moves a saved Product back to Cart items, keeping quantity.
+	MoveToCart(context.Context, *SavedItemRequest) (*CartResponse, error)
+	// ApplyCartOperations applies a batch of changes to a Cart in order. Either all of them are committed or none.
+	ApplyCartOperations(context.Context, *ApplyCartOperationsRequest) (*ApplyCartOperationsResponse, error)
+	// SnapshotCart freezes the current state of a Cart into an immutable Snapshot, e.g. when an order is placed.
+	SnapshotCart(context.Context, *CartRequest) (*Snapshot, error)
+	// GetSnapshot returns a Snapshot by ID.
+	GetSnapshot(context.Context, *SnapshotRequest) (*Snapshot, error)
+	// CloneCart creates a new Cart of the User with LineItems of a past Cart or Snapshot, e.g. to buy them again.
+	// The caller must be a member of the source Cart.
+	CloneCart(context.Context, *CloneCartRequest) (*CloneCartResponse, error)
+	// InviteMember adds a User to the shared Cart with a role or changes the role of an existing member.
+	InviteMember(context.Context, *InviteMemberRequest) (*CartMember, error)
+	// RemoveMember from the shared Cart. The owner can not be removed.
+	RemoveMember(context.Context, *RemoveMemberRequest) (*empty.Empty, error)
+	// ListMembers returns members of the Cart, the owner first.
+	ListMembers(context.Context, *CartRequest) (*ListMembersResponse, error)
+	// WatchCart streams the current Cart and then the Cart again after every change.
+	// The stream ends when the Cart is deleted.
+	WatchCart(*CartRequest, Carts_WatchCartServer) error
+}
+
+// UnimplementedCartsServer can be embedded to have forward compatible implementations.
+type UnimplementedCartsServer struct {
+}
+
+func (*UnimplementedCartsServer) CreateCart(ctx context.Context, req *CartCreateRequest) (*CartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCart not implemented")
+}
+func (*UnimplementedCartsServer) GetCart(ctx context.Context, req *CartRequest) (*CartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCart not implemented")
+}
+func (*UnimplementedCartsServer) EmptyCart(ctx context.Context, req *EmptyCartRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EmptyCart not implemented")
+}
+func (*UnimplementedCartsServer) DeleteCart(ctx context.Context, req *CartDeleteRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCart not implemented")
+}
+func (*UnimplementedCartsServer) RestoreCart(ctx context.Context, req *CartRequest) (*CartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreCart not implemented")
+}
+func (*UnimplementedCartsServer) AddProduct(ctx context.Context, req *AddProductRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddProduct not implemented")
+}
+func (*UnimplementedCartsServer) DelProduct(ctx context.Context, req *DelProductRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DelProduct not implemented")
+}
+func (*UnimplementedCartsServer) SetProductQuantity(ctx context.Context, req *SetProductQuantityRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetProductQuantity not implemented")
+}
+func (*UnimplementedCartsServer) RemoveProductUnits(ctx context.Context, req *RemoveProductUnitsRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveProductUnits not implemented")
+}
+func (*UnimplementedCartsServer) ListCarts(ctx context.Context, req *ListCartsRequest) (*ListCartsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCarts not implemented")
+}
+func (*UnimplementedCartsServer) GetActiveCart(ctx context.Context, req *ActiveCartRequest) (*CartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActiveCart not implemented")
+}
+func (*UnimplementedCartsServer) GetOrCreateCart(ctx context.Context, req *CartCreateRequest) (*CartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrCreateCart not implemented")
+}
+func (*UnimplementedCartsServer) MergeCarts(ctx context.Context, req *MergeCartsRequest) (*CartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeCarts not implemented")
+}
+func (*UnimplementedCartsServer) BeginCheckout(ctx context.Context, req *CartRequest) (*CartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BeginCheckout not implemented")
+}
+func (*UnimplementedCartsServer) CompleteOrder(ctx context.Context, req *CartRequest) (*CartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteOrder not implemented")
+}
+func (*UnimplementedCartsServer) ReopenCart(ctx context.Context, req *CartRequest) (*CartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReopenCart not implemented")
+}
+func (*UnimplementedCartsServer) AbandonCart(ctx context.Context, req *CartRequest) (*CartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AbandonCart not implemented")
+}
+func (*UnimplementedCartsServer) ApplyCoupon(ctx context.Context, req *CouponRequest) (*CartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyCoupon not implemented")
+}
+func (*UnimplementedCartsServer) RemoveCoupon(ctx context.Context, req *CouponRequest) (*CartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveCoupon not implemented")
+}
+func (*UnimplementedCartsServer) RenewReservations(ctx context.Context, req *CartRequest) (*CartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewReservations not implemented")
+}
+func (*UnimplementedCartsServer) GetCartHistory(ctx context.Context, req *CartHistoryRequest) (*CartHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCartHistory not implemented")
+}
+func (*UnimplementedCartsServer) SaveForLater(ctx context.Context, req *SavedItemRequest) (*CartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveForLater not implemented")
+}
+func (*UnimplementedCartsServer) MoveToCart(ctx context.Context, req *SavedItemRequest) (*CartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveToCart not implemented")
+}
+func (*UnimplementedCartsServer) ApplyCartOperations(ctx context.Context, req *ApplyCartOperationsRequest) (*ApplyCartOperationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyCartOperations not implemented")
+}
+func (*UnimplementedCartsServer) SnapshotCart(ctx context.Context, req *CartRequest) (*Snapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotCart not implemented")
+}
+func (*UnimplementedCartsServer) GetSnapshot(ctx context.Context, req *SnapshotRequest) (*Snapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSnapshot not implemented")
+}
+func (*UnimplementedCartsServer) CloneCart(ctx context.Context, req *CloneCartRequest) (*CloneCartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneCart not implemented")
+}
+func (*UnimplementedCartsServer) InviteMember(ctx context.Context, req *InviteMemberRequest) (*CartMember, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteMember not implemented")
+}
+func (*UnimplementedCartsServer) RemoveMember(ctx context.Context, req *RemoveMemberRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveMember not implemented")
+}
+func (*UnimplementedCartsServer) ListMembers(ctx context.Context, req *CartRequest) (*ListMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMembers not implemented")
+}
+func (*UnimplementedCartsServer) WatchCart(req *CartRequest, srv Carts_WatchCartServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchCart not implemented")
+}
+
+func RegisterCartsServer(s *grpc.Server, srv CartsServer) {
+	s.RegisterService(&_Carts_serviceDesc, srv)
+}
+
+func _Carts_CreateCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).CreateCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/CreateCart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).CreateCart(ctx, req.(*CartCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_GetCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).GetCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/GetCart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).GetCart(ctx, req.(*CartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_EmptyCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyCartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).EmptyCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/EmptyCart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).EmptyCart(ctx, req.(*EmptyCartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_DeleteCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).DeleteCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/DeleteCart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).DeleteCart(ctx, req.(*CartDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_RestoreCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).RestoreCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/RestoreCart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).RestoreCart(ctx, req.(*CartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_AddProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).AddProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/AddProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).AddProduct(ctx, req.(*AddProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_DelProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DelProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).DelProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/DelProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).DelProduct(ctx, req.(*DelProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_SetProductQuantity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetProductQuantityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).SetProductQuantity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/SetProductQuantity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).SetProductQuantity(ctx, req.(*SetProductQuantityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_RemoveProductUnits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveProductUnitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).RemoveProductUnits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/RemoveProductUnits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).RemoveProductUnits(ctx, req.(*RemoveProductUnitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_ListCarts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCartsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).ListCarts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/ListCarts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).ListCarts(ctx, req.(*ListCartsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_GetActiveCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActiveCartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).GetActiveCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/GetActiveCart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).GetActiveCart(ctx, req.(*ActiveCartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_GetOrCreateCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).GetOrCreateCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/GetOrCreateCart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).GetOrCreateCart(ctx, req.(*CartCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_MergeCarts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeCartsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).MergeCarts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/MergeCarts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).MergeCarts(ctx, req.(*MergeCartsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_BeginCheckout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).BeginCheckout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/BeginCheckout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).BeginCheckout(ctx, req.(*CartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_CompleteOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).CompleteOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/CompleteOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).CompleteOrder(ctx, req.(*CartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_ReopenCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).ReopenCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/ReopenCart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).ReopenCart(ctx, req.(*CartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_AbandonCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).AbandonCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/AbandonCart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).AbandonCart(ctx, req.(*CartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_ApplyCoupon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CouponRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).ApplyCoupon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/ApplyCoupon",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).ApplyCoupon(ctx, req.(*CouponRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_RemoveCoupon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CouponRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).RemoveCoupon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/RemoveCoupon",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).RemoveCoupon(ctx, req.(*CouponRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_RenewReservations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).RenewReservations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/RenewReservations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).RenewReservations(ctx, req.(*CartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_GetCartHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).GetCartHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/GetCartHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).GetCartHistory(ctx, req.(*CartHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_SaveForLater_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SavedItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).SaveForLater(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/SaveForLater",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).SaveForLater(ctx, req.(*SavedItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_MoveToCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SavedItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).MoveToCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/MoveToCart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).MoveToCart(ctx, req.(*SavedItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_ApplyCartOperations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyCartOperationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).ApplyCartOperations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/ApplyCartOperations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).ApplyCartOperations(ctx, req.(*ApplyCartOperationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_SnapshotCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).SnapshotCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/SnapshotCart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).SnapshotCart(ctx, req.(*CartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_GetSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).GetSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/GetSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).GetSnapshot(ctx, req.(*SnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_CloneCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneCartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).CloneCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/CloneCart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).CloneCart(ctx, req.(*CloneCartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_InviteMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).InviteMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/InviteMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).InviteMember(ctx, req.(*InviteMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_RemoveMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).RemoveMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/RemoveMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).RemoveMember(ctx, req.(*RemoveMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_ListMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartsServer).ListMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Carts/ListMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartsServer).ListMembers(ctx, req.(*CartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Carts_WatchCart_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CartRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CartsServer).WatchCart(m, &cartsWatchCartServer{stream})
+}
+
+type Carts_WatchCartServer interface {
+	Send(*CartResponse) error
+	grpc.ServerStream
+}
+
+type cartsWatchCartServer struct {
+	grpc.ServerStream
+}
+
+func (x *cartsWatchCartServer) Send(m *CartResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Carts_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "cooldryplace.protobuf.Carts",
+	HandlerType: (*CartsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateCart",
+			Handler:    _Carts_CreateCart_Handler,
+		},
+		{
+			MethodName: "GetCart",
+			Handler:    _Carts_GetCart_Handler,
+		},
+		{
+			MethodName: "EmptyCart",
+			Handler:    _Carts_EmptyCart_Handler,
+		},
+		{
+			MethodName: "DeleteCart",
+			Handler:    _Carts_DeleteCart_Handler,
+		},
+		{
+			MethodName: "RestoreCart",
+			Handler:    _Carts_RestoreCart_Handler,
+		},
+		{
+			MethodName: "AddProduct",
+			Handler:    _Carts_AddProduct_Handler,
+		},
+		{
+			MethodName: "DelProduct",
+			Handler:    _Carts_DelProduct_Handler,
+		},
+		{
+			MethodName: "SetProductQuantity",
+			Handler:    _Carts_SetProductQuantity_Handler,
+		},
+		{
+			MethodName: "RemoveProductUnits",
+			Handler:    _Carts_RemoveProductUnits_Handler,
+		},
+		{
+			MethodName: "ListCarts",
+			Handler:    _Carts_ListCarts_Handler,
+		},
+		{
+			MethodName: "GetActiveCart",
+			Handler:    _Carts_GetActiveCart_Handler,
+		},
+		{
+			MethodName: "GetOrCreateCart",
+			Handler:    _Carts_GetOrCreateCart_Handler,
+		},
+		{
+			MethodName: "MergeCarts",
+			Handler:    _Carts_MergeCarts_Handler,
+		},
+		{
+			MethodName: "BeginCheckout",
+			Handler:    _Carts_BeginCheckout_Handler,
+		},
+		{
+			MethodName: "CompleteOrder",
+			Handler:    _Carts_CompleteOrder_Handler,
+		},
+		{
+			MethodName: "ReopenCart",
+			Handler:    _Carts_ReopenCart_Handler,
+		},
+		{
+			MethodName: "AbandonCart",
+			Handler:    _Carts_AbandonCart_Handler,
+		},
+		{
+			MethodName: "ApplyCoupon",
+			Handler:    _Carts_ApplyCoupon_Handler,
+		},
+		{
+			MethodName: "RemoveCoupon",
+			Handler:    _Carts_RemoveCoupon_Handler,
+		},
+		{
+			MethodName: "RenewReservations",
+			Handler:    _Carts_RenewReservations_Handler,
+		},
+		{
+			MethodName: "GetCartHistory",
+			Handler:    _Carts_GetCartHistory_Handler,
+		},
+		{
+			MethodName: "SaveForLater",
+			Handler:    _Carts_SaveForLater_Handler,
+		},
+		{
+			MethodName: "MoveToCart",
+			Handler:    _Carts_MoveToCart_Handler,
+		},
+		{
+			MethodName: "ApplyCartOperations",
+			Handler:    _Carts_ApplyCartOperations_Handler,
+		},
+		{
+			MethodName: "SnapshotCart",
+			Handler:    _Carts_SnapshotCart_Handler,
+		},
+		{
+			MethodName: "GetSnapshot",
+			Handler:    _Carts_GetSnapshot_Handler,
+		},
+		{
+			MethodName: "CloneCart",
+			Handler:    _Carts_CloneCart_Handler,
+		},
+		{
+			MethodName: "InviteMember",
+			Handler:    _Carts_InviteMember_Handler,
+		},
+		{
+			MethodName: "RemoveMember",
+			Handler:    _Carts_RemoveMember_Handler,
+		},
+		{
+			MethodName: "ListMembers",
+			Handler:    _Carts_ListMembers_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchCart",
+			Handler:       _Carts_WatchCart_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "cart_service.proto",
+}
@@ -0,0 +1,229 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: product_service.proto
+
+package proto
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// ProductRequest is used to fetch Product details.
+type ProductRequest struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProductRequest) Reset()         { *m = ProductRequest{} }
+func (m *ProductRequest) String() string { return proto.CompactTextString(m) }
+func (*ProductRequest) ProtoMessage()    {}
+func (*ProductRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_64a1a24e6b7d7ed5, []int{0}
+}
+
+func (m *ProductRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProductRequest.Unmarshal(m, b)
+}
+func (m *ProductRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProductRequest.Marshal(b, m, deterministic)
+}
+func (m *ProductRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProductRequest.Merge(m, src)
+}
+func (m *ProductRequest) XXX_Size() int {
+	return xxx_messageInfo_ProductRequest.Size(m)
+}
+func (m *ProductRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProductRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProductRequest proto.InternalMessageInfo
+
+func (m *ProductRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+// Product details relevant to Carts.
+type Product struct {
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// active is false for discontinued Products, they can not be ordered.
+	Active bool `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`
+	// maxOrderQuantity limits quantity of the Product in a single Cart, zero means no limit.
+	MaxOrderQuantity     uint32   `protobuf:"varint,3,opt,name=maxOrderQuantity,proto3" json:"maxOrderQuantity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Product) Reset()         { *m = Product{} }
+func (m *Product) String() string { return proto.CompactTextString(m) }
+func (*Product) ProtoMessage()    {}
+func (*Product) Descriptor() ([]byte, []int) {
+	return fileDescriptor_64a1a24e6b7d7ed5, []int{1}
+}
+
+func (m *Product) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Product.Unmarshal(m, b)
+}
+func (m *Product) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Product.Marshal(b, m, deterministic)
+}
+func (m *Product) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Product.Merge(m, src)
+}
+func (m *Product) XXX_Size() int {
+	return xxx_messageInfo_Product.Size(m)
+}
+func (m *Product) XXX_DiscardUnknown() {
+	xxx_messageInfo_Product.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Product proto.InternalMessageInfo
+
+func (m *Product) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Product) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+func (m *Product) GetMaxOrderQuantity() uint32 {
+	if m != nil {
+		return m.MaxOrderQuantity
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*ProductRequest)(nil), "cooldryplace.protobuf.ProductRequest")
+	proto.RegisterType((*Product)(nil), "cooldryplace.protobuf.Product")
+}
+
+func init() { proto.RegisterFile("product_service.proto", fileDescriptor_64a1a24e6b7d7ed5) }
+
+var fileDescriptor_64a1a24e6b7d7ed5 = []byte{
+	// 204 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x2d, 0x28, 0xca, 0x4f,
+	0x29, 0x4d, 0x2e, 0x89, 0x2f, 0x4e, 0x2d, 0x2a, 0xcb, 0x4c, 0x4e, 0xd5, 0x2b, 0x28, 0xca, 0x2f,
+	0xc9, 0x17, 0x12, 0x4d, 0xce, 0xcf, 0xcf, 0x49, 0x29, 0xaa, 0x2c, 0xc8, 0x49, 0x84, 0x89, 0x25,
+	0x95, 0xa6, 0x29, 0x29, 0x70, 0xf1, 0x05, 0x40, 0xd4, 0x07, 0xa5, 0x16, 0x96, 0xa6, 0x16, 0x97,
+	0x08, 0xf1, 0x71, 0x31, 0x65, 0xa6, 0x48, 0x30, 0x2a, 0x30, 0x6a, 0x30, 0x07, 0x31, 0x65, 0xa6,
+	0x28, 0xc5, 0x72, 0xb1, 0x43, 0x55, 0xa0, 0x4b, 0x09, 0x89, 0x71, 0xb1, 0x25, 0x26, 0x97, 0x64,
+	0x96, 0xa5, 0x4a, 0x30, 0x29, 0x30, 0x6a, 0x70, 0x04, 0x41, 0x79, 0x42, 0x5a, 0x5c, 0x02, 0xb9,
+	0x89, 0x15, 0xfe, 0x45, 0x29, 0xa9, 0x45, 0x81, 0xa5, 0x89, 0x79, 0x25, 0x99, 0x25, 0x95, 0x12,
+	0xcc, 0x0a, 0x8c, 0x1a, 0xbc, 0x41, 0x18, 0xe2, 0x46, 0xf1, 0x5c, 0x1c, 0x50, 0xe3, 0x8b, 0x85,
+	0x82, 0xb9, 0xb8, 0xdc, 0x53, 0x4b, 0x60, 0xb6, 0xa9, 0xea, 0x61, 0x75, 0xb2, 0x1e, 0xaa, 0x7b,
+	0xa5, 0xe4, 0xf0, 0x2b, 0x73, 0x52, 0x89, 0x52, 0x4a, 0xcf, 0x2c, 0xc9, 0x28, 0x4d, 0xd2, 0x4b,
+	0xce, 0xcf, 0xd5, 0x47, 0x56, 0xab, 0x9f, 0x9c, 0x58, 0x54, 0xa2, 0x0f, 0xd1, 0xc0, 0x06, 0xa6,
+	0x8c, 0x01, 0x03, 0x00, 0x5c, 0x60, 0xa9, 0xd6, 0x3e, 0x01, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ProductsClient is the client API for Products service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ProductsClient interface {
+	// GetProduct returns Product with provided ID or NOT_FOUND status.
+	GetProduct(ctx context.Context, in *ProductRequest, opts ...grpc.CallOption) (*Product, error)
+}
+
+type productsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewProductsClient(cc *grpc.ClientConn) ProductsClient {
+	return &productsClient{cc}
+}
+
+func (c *productsClient) GetProduct(ctx context.Context, in *ProductRequest, opts ...grpc.CallOption) (*Product, error) {
+	out := new(Product)
+	err := c.cc.Invoke(ctx, "/cooldryplace.protobuf.Products/GetProduct", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProductsServer is the server API for Products service.
+type ProductsServer interface {
+	// GetProduct returns Product with provided ID or NOT_FOUND status.
+	GetProduct(context.Context, *ProductRequest) (*Product, error)
+}
+
+// UnimplementedProductsServer can be embedded to have forward compatible implementations.
+type UnimplementedProductsServer struct {
+}
+
+func (*UnimplementedProductsServer) GetProduct(ctx context.Context, req *ProductRequest) (*Product, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProduct not implemented")
+}
+
+func RegisterProductsServer(s *grpc.Server, srv ProductsServer) {
+	s.RegisterService(&_Products_serviceDesc, srv)
+}
+
+func _Products_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductsServer).GetProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cooldryplace.protobuf.Products/GetProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductsServer).GetProduct(ctx, req.(*ProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Products_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "cooldryplace.protobuf.Products",
+	HandlerType: (*ProductsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetProduct",
+			Handler:    _Products_GetProduct_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "product_service.proto",
+}
@@ -0,0 +1,46 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// DefaultRetention is how long deleted Carts can be restored unless Carts are configured otherwise.
+const DefaultRetention = 30 * 24 * time.Hour
+
+var (
+	errCartNotDeleted   = errors.New("cart is not deleted")
+	errRetentionExpired = errors.New("cart was deleted before the retention period and can not be restored")
+)
+
+// WithRetention sets how long deleted Carts can be restored. Zero retention allows to restore Carts until they are purged.
+func WithRetention(d time.Duration) Option {
+	return func(c *Carts) {
+		c.retention = d
+	}
+}
+
+// Restore a deleted Cart within the retention period and return it. Stock released on delete is not reserved again.
+func (c *Carts) Restore(ctx context.Context, cartID int64) (Cart, error) {
+	if err := c.authorize(ctx, cartID, RoleOwner); err != nil {
+		return Cart{}, err
+	}
+
+	var deletedAfter time.Time
+	if c.retention > 0 {
+		deletedAfter = time.Now().Add(-c.retention)
+	}
+
+	if err := c.storage.RestoreCart(ctx, cartID, deletedAfter); err != nil {
+		if err != errNotFound {
+			log.Printf("Failed to restore the Cart: %d, error: %s", cartID, err)
+		}
+		return Cart{}, err
+	}
+
+	c.changes.notify(cartID)
+
+	return c.Cart(ctx, cartID)
+}
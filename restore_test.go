@@ -0,0 +1,79 @@
+package cart
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRestore(t *testing.T) {
+	cases := []struct {
+		name          string
+		opts          []Option
+		expectedSince time.Duration
+	}{
+		{
+			name:          "Default retention",
+			expectedSince: DefaultRetention,
+		},
+		{
+			name:          "Custom retention",
+			opts:          []Option{WithRetention(time.Hour)},
+			expectedSince: time.Hour,
+		},
+		{
+			name: "No retention",
+			opts: []Option{WithRetention(0)},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var deletedAfter time.Time
+
+			storage := &StorageMock{
+				RestoreCartFunc: func(ctx context.Context, cartID int64, after time.Time) error {
+					deletedAfter = after
+					return nil
+				},
+				CartByIDFunc: func(ctx context.Context, id int64) (Cart, error) {
+					return Cart{ID: id}, nil
+				},
+			}
+
+			before := time.Now()
+
+			cart, err := New(storage, c.opts...).Restore(WithTrustedService(context.Background()), 1)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			if cart.ID != 1 {
+				t.Errorf("Got Cart: %d, expected: 1", cart.ID)
+			}
+
+			if c.expectedSince == 0 {
+				if !deletedAfter.IsZero() {
+					t.Errorf("Got deleted after: %s, expected any deleted Cart to be restored", deletedAfter)
+				}
+				return
+			}
+
+			if earliest := before.Add(-c.expectedSince); deletedAfter.Before(earliest) || deletedAfter.After(time.Now().Add(-c.expectedSince)) {
+				t.Errorf("Got deleted after: %s, expected about %s", deletedAfter, earliest)
+			}
+		})
+	}
+}
+
+func TestRestoreNotDeleted(t *testing.T) {
+	storage := &StorageMock{
+		RestoreCartFunc: func(ctx context.Context, cartID int64, deletedAfter time.Time) error {
+			return errCartNotDeleted
+		},
+	}
+
+	if _, err := New(storage).Restore(WithTrustedService(context.Background()), 1); err != errCartNotDeleted {
+		t.Errorf("Got error: %v, expected: %v", err, errCartNotDeleted)
+	}
+}
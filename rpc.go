@@ -2,22 +2,44 @@ package cart
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
+	"strconv"
 
-	"github.com/cooldryplace/proto"
+	"github.com/cooldryplace/cart/proto"
 
+	protobuf "github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
+const (
+	// idempotencyKeyHeader is gRPC metadata key used to pass idempotency key.
+	idempotencyKeyHeader = "idempotency-key"
+	// actorHeader is gRPC metadata key used to identify who makes Cart changes.
+	actorHeader = "actor"
+	// callerHeader is gRPC metadata key used to pass ID of the User making the call.
+	callerHeader = "caller-id"
+	// serviceKeyHeader is gRPC metadata key used by trusted services to authenticate.
+	serviceKeyHeader = "service-key"
+)
+
 var emptyResp = &empty.Empty{}
 
 func toProtoLineItem(li LineItem) *proto.LineItem {
 	return &proto.LineItem{
-		ProductId: li.ProductID,
-		Quantity:  li.Quantity,
+		ProductId:     li.ProductID,
+		Quantity:      li.Quantity,
+		UnitPrice:     li.UnitPrice,
+		LineTotal:     li.LineTotal,
+		ReservationId: li.ReservationID,
+		Options:       li.Options,
+		Note:          li.Note,
 	}
 }
 
@@ -31,6 +53,54 @@ func toProtoLineItems(lis []LineItem) []*proto.LineItem {
 	return result
 }
 
+func toProtoDiscounts(ds []Discount) []*proto.Discount {
+	result := make([]*proto.Discount, 0, len(ds))
+
+	for _, d := range ds {
+		result = append(result, &proto.Discount{
+			Code:        d.Code,
+			ProductId:   d.ProductID,
+			Description: d.Description,
+			Amount:      d.Amount,
+		})
+	}
+
+	return result
+}
+
+var protoStatuses = map[Status]proto.CartStatus{
+	StatusOpen:        proto.CartStatus_STATUS_OPEN,
+	StatusCheckingOut: proto.CartStatus_STATUS_CHECKING_OUT,
+	StatusOrdered:     proto.CartStatus_STATUS_ORDERED,
+	StatusAbandoned:   proto.CartStatus_STATUS_ABANDONED,
+}
+
+var protoRoles = map[Role]proto.MemberRole{
+	RoleViewer: proto.MemberRole_MEMBER_ROLE_VIEWER,
+	RoleEditor: proto.MemberRole_MEMBER_ROLE_EDITOR,
+	RoleOwner:  proto.MemberRole_MEMBER_ROLE_OWNER,
+}
+
+// roles has no MEMBER_ROLE_UNSPECIFIED, it maps to the zero Role that InviteMember rejects as unknown.
+var roles = map[proto.MemberRole]Role{
+	proto.MemberRole_MEMBER_ROLE_VIEWER: RoleViewer,
+	proto.MemberRole_MEMBER_ROLE_EDITOR: RoleEditor,
+	proto.MemberRole_MEMBER_ROLE_OWNER:  RoleOwner,
+}
+
+func toProtoMember(m Member) (*proto.CartMember, error) {
+	createdAt, err := ptypes.TimestampProto(m.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert creation time to ptypes.Timestamp: %s", err)
+	}
+
+	return &proto.CartMember{
+		UserId:    m.UserID,
+		Role:      protoRoles[m.Role],
+		CreatedAt: createdAt,
+	}, nil
+}
+
 func toProtoCart(c Cart) (*proto.Cart, error) {
 	createdAt, err := ptypes.TimestampProto(c.CreatedAt)
 	if err != nil {
@@ -42,14 +112,158 @@ func toProtoCart(c Cart) (*proto.Cart, error) {
 	}
 
 	return &proto.Cart{
-		Id:        c.ID,
-		UserId:    c.UserID,
-		Items:     toProtoLineItems(c.Items),
+		Id:            c.ID,
+		UserId:        c.UserID,
+		Items:         toProtoLineItems(c.Items),
+		SavedItems:    toProtoLineItems(c.SavedItems),
+		CreatedAt:     createdAt,
+		UpdatedAt:     updatedAt,
+		Status:        protoStatuses[c.Status],
+		Version:       c.Version,
+		Subtotal:      c.Subtotal,
+		Currency:      c.Currency,
+		Coupons:       c.Coupons,
+		Discounts:     toProtoDiscounts(c.Discounts),
+		DiscountTotal: c.DiscountTotal,
+		Total:         c.Total,
+	}, nil
+}
+
+func toProtoHistoryEntry(h HistoryEntry) (*proto.HistoryEntry, error) {
+	createdAt, err := ptypes.TimestampProto(h.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert creation time to ptypes.Timestamp: %s", err)
+	}
+
+	return &proto.HistoryEntry{
+		Id:          h.ID,
+		CartId:      h.CartID,
+		Actor:       h.Actor,
+		Operation:   string(h.Operation),
+		ProductId:   h.ProductID,
+		OldQuantity: h.OldQuantity,
+		NewQuantity: h.NewQuantity,
+		Detail:      h.Detail,
+		CreatedAt:   createdAt,
+	}, nil
+}
+
+func toProtoOperationResults(results []OperationResult) []*proto.OperationResult {
+	pResults := make([]*proto.OperationResult, 0, len(results))
+
+	for _, r := range results {
+		pr := &proto.OperationResult{Quantity: r.Quantity, Skipped: r.Skipped}
+		if r.Err != nil {
+			pr.Code = int32(errorCode(r.Err))
+			pr.Error = r.Err.Error()
+		}
+		pResults = append(pResults, pr)
+	}
+
+	return pResults
+}
+
+func toProtoSnapshot(s Snapshot) (*proto.Snapshot, error) {
+	cart, err := toProtoCart(s.Cart)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt, err := ptypes.TimestampProto(s.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert creation time to ptypes.Timestamp: %s", err)
+	}
+
+	return &proto.Snapshot{
+		Id:        s.ID,
+		Cart:      cart,
+		Hash:      s.Hash,
 		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
 	}, nil
 }
 
+// cartResponse converts the Cart into gRPC response.
+func cartResponse(c Cart) (*proto.CartResponse, error) {
+	pCart, err := toProtoCart(c)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to convert the Cart: %s", err)
+	}
+
+	return &proto.CartResponse{Cart: pCart}, nil
+}
+
+// errorCode returns gRPC status code matching the error.
+func errorCode(err error) codes.Code {
+	if v, ok := err.(*RuleViolation); ok {
+		if v.lineRule() {
+			return codes.InvalidArgument
+		}
+		return codes.FailedPrecondition
+	}
+
+	switch err {
+	case errNotFound, errLineItemNotFound, errCouponNotFound, errCouponNotApplied, errProductNotFound, errSnapshotNotFound,
+		errMemberNotFound:
+		return codes.NotFound
+	case errSameCart, errUnknownMergeStrategy, errInvalidPageToken, errInvalidCouponCode, errProductInactive,
+		errNoOperations, errTooManyOperations, errUnknownOperation, errInvalidQuantity, errInvalidOptions, errNoteTooLong,
+		errInvalidCloneSource, errUnknownRole:
+		return codes.InvalidArgument
+	case errNotEnoughQuantity, errCartNotOpen, errInvalidTransition, errPriceNotFound, errCurrencyMismatch,
+		errOutOfStock, errReservationExpired, errCartNotCheckingOut, errCartNotDeleted, errRetentionExpired,
+		errOwnerMembership:
+		return codes.FailedPrecondition
+	case errPermissionDenied:
+		return codes.PermissionDenied
+	case errUnauthenticated:
+		return codes.Unauthenticated
+	case errReservationsDisabled:
+		return codes.Unimplemented
+	case errQuantityTooLarge:
+		return codes.OutOfRange
+	case errVersionMismatch, errRequestInProgress:
+		return codes.Aborted
+	}
+
+	return codes.Internal
+}
+
+// errorStatus returns gRPC status error with the code of err. RuleViolations are described in status details:
+// BadRequest for quantity of a LineItem and PreconditionFailure for content of the whole Cart.
+func errorStatus(err error, msg string) error {
+	st := status.New(errorCode(err), fmt.Sprintf("%s: %s", msg, err))
+
+	v, ok := err.(*RuleViolation)
+	if !ok {
+		return st.Err()
+	}
+
+	var detail protobuf.Message
+	if v.lineRule() {
+		detail = &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+				Field:       fmt.Sprintf("quantity[productId=%d]", v.ProductID),
+				Description: fmt.Sprintf("%s: %s", v.Rule, v.Description),
+			}},
+		}
+	} else {
+		detail = &errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{{
+				Type:        v.Rule,
+				Subject:     "cart",
+				Description: v.Description,
+			}},
+		}
+	}
+
+	withDetails, derr := st.WithDetails(detail)
+	if derr != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
+
 // Server implements protobuf Carts service.
 type Server struct {
 	carts *Carts
@@ -60,41 +274,256 @@ func NewServer(c *Carts) *Server {
 	return &Server{carts: c}
 }
 
+// NewActorInterceptor returns an interceptor that attributes Cart changes made by the call to the actor provided in "actor"
+// gRPC metadata. User ID provided in "caller-id" metadata is checked against Cart members. Calls without a caller are
+// rejected, unless they come from a trusted service that provides one of serviceKeys in "service-key" metadata.
+func NewActorInterceptor(serviceKeys []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := actorContext(ctx, serviceKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// NewActorStreamInterceptor returns an interceptor that identifies callers of streaming calls as NewActorInterceptor does.
+func NewActorStreamInterceptor(serviceKeys []string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := actorContext(ss.Context(), serviceKeys)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &actorStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// actorStream is a ServerStream with the context of the identified caller.
+type actorStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *actorStream) Context() context.Context {
+	return s.ctx
+}
+
+// actorContext returns a context with the actor, the caller and the trusted service marker provided in gRPC metadata.
+func actorContext(ctx context.Context, serviceKeys []string) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+
+	if actors := md.Get(actorHeader); len(actors) > 0 {
+		ctx = WithActor(ctx, actors[0])
+	}
+	if callers := md.Get(callerHeader); len(callers) > 0 {
+		userID, err := strconv.ParseInt(callers[0], 10, 64)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid caller ID: %q", callers[0])
+		}
+		ctx = WithCaller(ctx, userID)
+	}
+	if keys := md.Get(serviceKeyHeader); len(keys) > 0 {
+		if !validServiceKey(keys[0], serviceKeys) {
+			return nil, status.Error(codes.Unauthenticated, "invalid service key")
+		}
+		ctx = WithTrustedService(ctx)
+	}
+
+	return ctx, nil
+}
+
+// validServiceKey reports whether the key is one of the configured service keys.
+func validServiceKey(key string, serviceKeys []string) bool {
+	for _, k := range serviceKeys {
+		if k != "" && subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// idempotencyKey returns the key provided in the request or in gRPC metadata.
+func idempotencyKey(ctx context.Context, key string) string {
+	if key != "" {
+		return key
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get(idempotencyKeyHeader); len(keys) > 0 {
+			return keys[0]
+		}
+	}
+
+	return ""
+}
+
+// uncommitted is returned by requests that report their failure in the response, e.g. not committed batches.
+// The response is returned to the caller, but not stored for replays, so the request can be corrected and retried.
+type uncommitted struct {
+	resp protobuf.Message
+}
+
+func (uncommitted) Error() string {
+	return "request is not committed"
+}
+
+// idempotent calls f once for each idempotency key of the method. Retries with the same key
+// get the original response unmarshaled into resp. Requests without a key always call f.
+func (s *Server) idempotent(ctx context.Context, key, method string, resp protobuf.Message, f func() (protobuf.Message, error)) (protobuf.Message, error) {
+	key = idempotencyKey(ctx, key)
+	if key == "" {
+		resp, err := f()
+		if u, ok := err.(uncommitted); ok {
+			return u.resp, nil
+		}
+		return resp, err
+	}
+
+	b, err := s.carts.Idempotent(ctx, key, method, func() ([]byte, error) {
+		resp, err := f()
+		if err != nil {
+			return nil, err
+		}
+		return protobuf.Marshal(resp)
+	})
+	if err != nil {
+		if u, ok := err.(uncommitted); ok {
+			return u.resp, nil
+		}
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, errorStatus(err, "failed to process the request")
+	}
+
+	if err := protobuf.Unmarshal(b, resp); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to unmarshal stored response: %s", err)
+	}
+
+	return resp, nil
+}
+
 // AddProduct to a Cart.
 func (s *Server) AddProduct(ctx context.Context, req *proto.AddProductRequest) (*empty.Empty, error) {
 	if req.Quantity == 0 {
 		return nil, status.Error(codes.InvalidArgument, "failed to add the product: wrong quantity")
 	}
 
-	if err := s.carts.AddProduct(ctx, req.CartId, req.ProductId, req.Quantity); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to add the Product: %s", err)
+	ctx = WithExpectedVersion(ctx, req.ExpectedVersion)
+
+	resp, err := s.idempotent(ctx, req.IdempotencyKey, "AddProduct", &empty.Empty{}, func() (protobuf.Message, error) {
+		if err := s.carts.AddProduct(ctx, req.CartId, req.ProductId, req.Options, req.Quantity, req.Note); err != nil {
+			if err == errNotFound {
+				return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.CartId)
+			}
+			return nil, errorStatus(err, "failed to add the Product")
+		}
+
+		return emptyResp, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return emptyResp, nil
+	return resp.(*empty.Empty), nil
 }
 
 // DelProduct removes product from a Cart.
 func (s *Server) DelProduct(ctx context.Context, req *proto.DelProductRequest) (*empty.Empty, error) {
-	if err := s.carts.DeleteProduct(ctx, req.CartId, req.ProductId); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to delete the Product: %s", err)
+	ctx = WithExpectedVersion(ctx, req.ExpectedVersion)
+
+	resp, err := s.idempotent(ctx, req.IdempotencyKey, "DelProduct", &empty.Empty{}, func() (protobuf.Message, error) {
+		if err := s.carts.DeleteProduct(ctx, req.CartId, req.ProductId, req.Options); err != nil {
+			if err == errNotFound {
+				return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.CartId)
+			}
+			return nil, errorStatus(err, "failed to delete the Product")
+		}
+
+		return emptyResp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(*empty.Empty), nil
+}
+
+// SetProductQuantity replaces quantity of a Product in a Cart, zero quantity removes the Product.
+func (s *Server) SetProductQuantity(ctx context.Context, req *proto.SetProductQuantityRequest) (*empty.Empty, error) {
+	ctx = WithExpectedVersion(ctx, req.ExpectedVersion)
+
+	if err := s.carts.SetProductQuantity(ctx, req.CartId, req.ProductId, req.Options, req.Quantity); err != nil {
+		if err == errNotFound {
+			return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.CartId)
+		}
+		return nil, errorStatus(err, "failed to set the Product quantity")
 	}
 
 	return emptyResp, nil
 }
 
+// RemoveProductUnits decreases quantity of a Product in a Cart.
+func (s *Server) RemoveProductUnits(ctx context.Context, req *proto.RemoveProductUnitsRequest) (*empty.Empty, error) {
+	if req.Quantity == 0 {
+		return nil, status.Error(codes.InvalidArgument, "failed to remove the product units: wrong quantity")
+	}
+
+	ctx = WithExpectedVersion(ctx, req.ExpectedVersion)
+
+	if err := s.carts.RemoveProductUnits(ctx, req.CartId, req.ProductId, req.Options, req.Quantity); err != nil {
+		if err == errNotFound {
+			return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.CartId)
+		}
+		return nil, errorStatus(err, "failed to remove the Product units")
+	}
+
+	return emptyResp, nil
+}
+
+// MergeCarts moves LineItems of the source Cart into the target Cart.
+func (s *Server) MergeCarts(ctx context.Context, req *proto.MergeCartsRequest) (*proto.CartResponse, error) {
+	ctx = WithExpectedVersion(ctx, req.ExpectedVersion)
+
+	cart, err := s.carts.Merge(ctx, req.SourceCartId, req.TargetCartId, MergeStrategy(req.Strategy))
+	if err != nil {
+		return nil, errorStatus(err, "failed to merge Carts")
+	}
+
+	return cartResponse(cart)
+}
+
 // CreateCart for a User.
 func (s *Server) CreateCart(ctx context.Context, req *proto.CartCreateRequest) (*proto.CartResponse, error) {
-	cart, err := s.carts.Create(ctx, req.UserId)
+	resp, err := s.idempotent(ctx, req.IdempotencyKey, "CreateCart", &proto.CartResponse{}, func() (protobuf.Message, error) {
+		cart, err := s.carts.Create(ctx, req.UserId)
+		if err != nil {
+			return nil, errorStatus(err, "failed to create the Cart")
+		}
+
+		return cartResponse(cart)
+	})
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create the Cart: %s", err)
+		return nil, err
 	}
 
-	pCart, err := toProtoCart(cart)
+	return resp.(*proto.CartResponse), nil
+}
+
+// GetOrCreateCart returns the active Cart of a User and creates one if there is none.
+func (s *Server) GetOrCreateCart(ctx context.Context, req *proto.CartCreateRequest) (*proto.CartResponse, error) {
+	cart, err := s.carts.GetOrCreate(ctx, req.UserId)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to convert the Cart: %s", err)
+		return nil, errorStatus(err, "failed to get or create the Cart")
 	}
 
-	return &proto.CartResponse{Cart: pCart}, nil
+	return cartResponse(cart)
 }
 
 // DeleteCart with the matching ID.
@@ -103,22 +532,44 @@ func (s *Server) DeleteCart(ctx context.Context, req *proto.CartDeleteRequest) (
 		if err == errNotFound {
 			return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.Id)
 		}
-		return nil, status.Errorf(codes.Internal, "failed to delete the Cart: %s", err)
+		return nil, errorStatus(err, "failed to delete the Cart")
 	}
 
 	return emptyResp, nil
 }
 
+// RestoreCart deleted within the retention period.
+func (s *Server) RestoreCart(ctx context.Context, req *proto.CartRequest) (*proto.CartResponse, error) {
+	cart, err := s.carts.Restore(ctx, req.Id)
+	if err != nil {
+		if err == errNotFound {
+			return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.Id)
+		}
+		return nil, errorStatus(err, "failed to restore the Cart")
+	}
+
+	return cartResponse(cart)
+}
+
 // EmptyCart deletes all LineItems from a Cart.
 func (s *Server) EmptyCart(ctx context.Context, req *proto.EmptyCartRequest) (*empty.Empty, error) {
-	if err := s.carts.Empty(ctx, req.CartId); err != nil {
-		if err == errNotFound {
-			return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.CartId)
+	ctx = WithExpectedVersion(ctx, req.ExpectedVersion)
+
+	resp, err := s.idempotent(ctx, req.IdempotencyKey, "EmptyCart", &empty.Empty{}, func() (protobuf.Message, error) {
+		if err := s.carts.Empty(ctx, req.CartId); err != nil {
+			if err == errNotFound {
+				return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.CartId)
+			}
+			return nil, errorStatus(err, "failed to empty the Cart")
 		}
-		return nil, status.Errorf(codes.Internal, "failed to empty the Cart: %s", err)
+
+		return emptyResp, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return emptyResp, nil
+	return resp.(*empty.Empty), nil
 }
 
 // GetCart returns current Cart state.
@@ -128,13 +579,410 @@ func (s *Server) GetCart(ctx context.Context, req *proto.CartRequest) (*proto.Ca
 		if err == errNotFound {
 			return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.Id)
 		}
-		return nil, status.Errorf(codes.Internal, "failed to get the Cart: %s", err)
+		return nil, errorStatus(err, "failed to get the Cart")
 	}
 
-	pCart, err := toProtoCart(cart)
+	return cartResponse(cart)
+}
+
+// ListCarts returns a page of User Carts.
+func (s *Server) ListCarts(ctx context.Context, req *proto.ListCartsRequest) (*proto.ListCartsResponse, error) {
+	page := Page{Token: req.PageToken, Size: int(req.PageSize)}
+
+	carts, next, err := s.carts.ListByUser(ctx, req.UserId, page)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to convert the Cart: %s", err)
+		return nil, errorStatus(err, "failed to list Carts")
 	}
 
-	return &proto.CartResponse{Cart: pCart}, nil
+	resp := &proto.ListCartsResponse{
+		Carts:         make([]*proto.Cart, 0, len(carts)),
+		NextPageToken: next,
+	}
+
+	for _, cart := range carts {
+		pCart, err := toProtoCart(cart)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert the Cart: %s", err)
+		}
+		resp.Carts = append(resp.Carts, pCart)
+	}
+
+	return resp, nil
+}
+
+// GetActiveCart returns the most recently updated Cart of a User.
+func (s *Server) GetActiveCart(ctx context.Context, req *proto.ActiveCartRequest) (*proto.CartResponse, error) {
+	cart, err := s.carts.ActiveCart(ctx, req.UserId)
+	if err != nil {
+		if err == errNotFound {
+			return nil, status.Errorf(codes.NotFound, "user with ID: %d has no carts", req.UserId)
+		}
+		return nil, errorStatus(err, "failed to get the active Cart")
+	}
+
+	return cartResponse(cart)
+}
+
+// BeginCheckout freezes the Cart while the Order is being placed.
+func (s *Server) BeginCheckout(ctx context.Context, req *proto.CartRequest) (*proto.CartResponse, error) {
+	cart, err := s.carts.BeginCheckout(ctx, req.Id)
+	if err != nil {
+		return nil, errorStatus(err, "failed to begin checkout")
+	}
+
+	return cartResponse(cart)
+}
+
+// CompleteOrder marks the Cart as ordered.
+func (s *Server) CompleteOrder(ctx context.Context, req *proto.CartRequest) (*proto.CartResponse, error) {
+	cart, err := s.carts.CompleteOrder(ctx, req.Id)
+	if err != nil {
+		return nil, errorStatus(err, "failed to complete the Order")
+	}
+
+	return cartResponse(cart)
+}
+
+// ReopenCart allows to modify the Cart again.
+func (s *Server) ReopenCart(ctx context.Context, req *proto.CartRequest) (*proto.CartResponse, error) {
+	cart, err := s.carts.Reopen(ctx, req.Id)
+	if err != nil {
+		return nil, errorStatus(err, "failed to reopen the Cart")
+	}
+
+	return cartResponse(cart)
+}
+
+// AbandonCart marks the Cart as abandoned.
+func (s *Server) AbandonCart(ctx context.Context, req *proto.CartRequest) (*proto.CartResponse, error) {
+	cart, err := s.carts.Abandon(ctx, req.Id)
+	if err != nil {
+		return nil, errorStatus(err, "failed to abandon the Cart")
+	}
+
+	return cartResponse(cart)
+}
+
+// ApplyCoupon to a Cart.
+func (s *Server) ApplyCoupon(ctx context.Context, req *proto.CouponRequest) (*proto.CartResponse, error) {
+	ctx = WithExpectedVersion(ctx, req.ExpectedVersion)
+
+	cart, err := s.carts.ApplyCoupon(ctx, req.CartId, req.Code)
+	if err != nil {
+		if err == errNotFound {
+			return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.CartId)
+		}
+		return nil, errorStatus(err, "failed to apply the coupon")
+	}
+
+	return cartResponse(cart)
+}
+
+// RemoveCoupon from a Cart.
+func (s *Server) RemoveCoupon(ctx context.Context, req *proto.CouponRequest) (*proto.CartResponse, error) {
+	ctx = WithExpectedVersion(ctx, req.ExpectedVersion)
+
+	cart, err := s.carts.RemoveCoupon(ctx, req.CartId, req.Code)
+	if err != nil {
+		if err == errNotFound {
+			return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.CartId)
+		}
+		return nil, errorStatus(err, "failed to remove the coupon")
+	}
+
+	return cartResponse(cart)
+}
+
+// RenewReservations extends stock reservations of the Cart being checked out.
+func (s *Server) RenewReservations(ctx context.Context, req *proto.CartRequest) (*proto.CartResponse, error) {
+	cart, err := s.carts.RenewReservations(ctx, req.Id)
+	if err != nil {
+		if err == errNotFound {
+			return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.Id)
+		}
+		return nil, errorStatus(err, "failed to renew reservations")
+	}
+
+	return cartResponse(cart)
+}
+
+// GetCartHistory returns a page of the Cart changes.
+func (s *Server) GetCartHistory(ctx context.Context, req *proto.CartHistoryRequest) (*proto.CartHistoryResponse, error) {
+	page := Page{Token: req.PageToken, Size: int(req.PageSize)}
+
+	entries, next, err := s.carts.History(ctx, req.CartId, page)
+	if err != nil {
+		return nil, errorStatus(err, "failed to get the Cart history")
+	}
+
+	resp := &proto.CartHistoryResponse{
+		Entries:       make([]*proto.HistoryEntry, 0, len(entries)),
+		NextPageToken: next,
+	}
+
+	for _, h := range entries {
+		pEntry, err := toProtoHistoryEntry(h)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert the history entry: %s", err)
+		}
+		resp.Entries = append(resp.Entries, pEntry)
+	}
+
+	return resp, nil
+}
+
+// SaveForLater moves a Product from Cart items to its saved items.
+func (s *Server) SaveForLater(ctx context.Context, req *proto.SavedItemRequest) (*proto.CartResponse, error) {
+	ctx = WithExpectedVersion(ctx, req.ExpectedVersion)
+
+	cart, err := s.carts.SaveForLater(ctx, req.CartId, req.ProductId, req.Options)
+	if err != nil {
+		if err == errNotFound {
+			return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.CartId)
+		}
+		return nil, errorStatus(err, "failed to save the Product for later")
+	}
+
+	return cartResponse(cart)
+}
+
+// MoveToCart moves a saved Product back to Cart items.
+func (s *Server) MoveToCart(ctx context.Context, req *proto.SavedItemRequest) (*proto.CartResponse, error) {
+	ctx = WithExpectedVersion(ctx, req.ExpectedVersion)
+
+	cart, err := s.carts.MoveToCart(ctx, req.CartId, req.ProductId, req.Options)
+	if err != nil {
+		if err == errNotFound {
+			return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.CartId)
+		}
+		return nil, errorStatus(err, "failed to move the Product to the Cart")
+	}
+
+	return cartResponse(cart)
+}
+
+// ApplyCartOperations applies a batch of changes to a Cart. Either all of them are committed or none.
+// Failure of an operation is reported in its result, errors not caused by operations fail the request.
+func (s *Server) ApplyCartOperations(ctx context.Context, req *proto.ApplyCartOperationsRequest) (*proto.ApplyCartOperationsResponse, error) {
+	ctx = WithExpectedVersion(ctx, req.ExpectedVersion)
+
+	ops := make([]Operation, 0, len(req.Operations))
+	for _, op := range req.Operations {
+		ops = append(ops, Operation{
+			Type:      OperationType(op.Type),
+			ProductID: op.ProductId,
+			Options:   op.Options,
+			Quantity:  op.Quantity,
+			Note:      op.Note,
+		})
+	}
+
+	resp, err := s.idempotent(ctx, req.IdempotencyKey, "ApplyCartOperations", &proto.ApplyCartOperationsResponse{}, func() (protobuf.Message, error) {
+		results, err := s.carts.Apply(ctx, req.CartId, ops)
+		if err != nil && (results == nil || errorCode(err) == codes.Internal) {
+			if err == errNotFound {
+				return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.CartId)
+			}
+			return nil, errorStatus(err, "failed to apply operations")
+		}
+
+		resp := &proto.ApplyCartOperationsResponse{
+			Committed: err == nil,
+			Results:   toProtoOperationResults(results),
+		}
+
+		if !resp.Committed {
+			return nil, uncommitted{resp: resp}
+		}
+
+		cart, err := s.carts.Cart(ctx, req.CartId)
+		if err != nil {
+			return nil, errorStatus(err, "failed to get the Cart")
+		}
+
+		if resp.Cart, err = toProtoCart(cart); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert the Cart: %s", err)
+		}
+
+		return resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(*proto.ApplyCartOperationsResponse), nil
+}
+
+// SnapshotCart freezes the current state of a Cart.
+func (s *Server) SnapshotCart(ctx context.Context, req *proto.CartRequest) (*proto.Snapshot, error) {
+	snapshot, err := s.carts.Snapshot(ctx, req.Id)
+	if err != nil {
+		if err == errNotFound {
+			return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.Id)
+		}
+		return nil, errorStatus(err, "failed to snapshot the Cart")
+	}
+
+	pSnapshot, err := toProtoSnapshot(snapshot)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to convert the Snapshot: %s", err)
+	}
+
+	return pSnapshot, nil
+}
+
+// GetSnapshot returns a Snapshot by ID.
+func (s *Server) GetSnapshot(ctx context.Context, req *proto.SnapshotRequest) (*proto.Snapshot, error) {
+	snapshot, err := s.carts.GetSnapshot(ctx, req.Id)
+	if err != nil {
+		return nil, errorStatus(err, "failed to get the Snapshot")
+	}
+
+	pSnapshot, err := toProtoSnapshot(snapshot)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to convert the Snapshot: %s", err)
+	}
+
+	return pSnapshot, nil
+}
+
+// CloneCart creates a new Cart of the User with LineItems of a past Cart or Snapshot.
+func (s *Server) CloneCart(ctx context.Context, req *proto.CloneCartRequest) (*proto.CloneCartResponse, error) {
+	if (req.CartId == 0) == (req.SnapshotId == 0) {
+		return nil, errorStatus(errInvalidCloneSource, "failed to clone the Cart")
+	}
+
+	resp, err := s.idempotent(ctx, req.IdempotencyKey, "CloneCart", &proto.CloneCartResponse{}, func() (protobuf.Message, error) {
+		var (
+			cart    Cart
+			skipped []LineItem
+			err     error
+		)
+
+		if req.CartId != 0 {
+			cart, skipped, err = s.carts.Clone(ctx, req.CartId, req.UserId, req.SkipUnavailable)
+		} else {
+			cart, skipped, err = s.carts.CloneSnapshot(ctx, req.SnapshotId, req.UserId, req.SkipUnavailable)
+		}
+		if err != nil {
+			if err == errNotFound {
+				return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.CartId)
+			}
+			return nil, errorStatus(err, "failed to clone the Cart")
+		}
+
+		pCart, err := toProtoCart(cart)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert the Cart: %s", err)
+		}
+
+		return &proto.CloneCartResponse{Cart: pCart, Skipped: toProtoLineItems(skipped)}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(*proto.CloneCartResponse), nil
+}
+
+// InviteMember adds a User to the shared Cart or changes the Role of a member.
+func (s *Server) InviteMember(ctx context.Context, req *proto.InviteMemberRequest) (*proto.CartMember, error) {
+	m, err := s.carts.InviteMember(ctx, req.CartId, req.UserId, roles[req.Role])
+	if err != nil {
+		if err == errNotFound {
+			return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.CartId)
+		}
+		return nil, errorStatus(err, "failed to invite the member")
+	}
+
+	pMember, err := toProtoMember(m)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to convert the member: %s", err)
+	}
+
+	return pMember, nil
+}
+
+// RemoveMember from the shared Cart.
+func (s *Server) RemoveMember(ctx context.Context, req *proto.RemoveMemberRequest) (*empty.Empty, error) {
+	if err := s.carts.RemoveMember(ctx, req.CartId, req.UserId); err != nil {
+		if err == errNotFound {
+			return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.CartId)
+		}
+		return nil, errorStatus(err, "failed to remove the member")
+	}
+
+	return emptyResp, nil
+}
+
+// ListMembers returns members of the Cart, the owner first.
+func (s *Server) ListMembers(ctx context.Context, req *proto.CartRequest) (*proto.ListMembersResponse, error) {
+	members, err := s.carts.Members(ctx, req.Id)
+	if err != nil {
+		if err == errNotFound {
+			return nil, status.Errorf(codes.NotFound, "cart with ID: %d not found", req.Id)
+		}
+		return nil, errorStatus(err, "failed to list members")
+	}
+
+	pMembers := make([]*proto.CartMember, 0, len(members))
+	for _, m := range members {
+		pm, err := toProtoMember(m)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert the member: %s", err)
+		}
+		pMembers = append(pMembers, pm)
+	}
+
+	return &proto.ListMembersResponse{Members: pMembers}, nil
+}
+
+// WatchCart sends the current Cart and then a new snapshot of the Cart after every change.
+// The stream ends when the Cart is deleted or the client goes away.
+func (s *Server) WatchCart(req *proto.CartRequest, stream proto.Carts_WatchCartServer) error {
+	ctx := stream.Context()
+
+	// Subscribe before the first read, so changes made in between are not missed.
+	changes, stop, err := s.carts.Watch(ctx, req.Id)
+	if err != nil {
+		if err == errNotFound {
+			return status.Errorf(codes.NotFound, "cart with ID: %d not found", req.Id)
+		}
+		return errorStatus(err, "failed to watch the Cart")
+	}
+	defer stop()
+
+	sent := false
+	var version int64
+
+	for {
+		cart, err := s.carts.Cart(ctx, req.Id)
+		if err != nil {
+			if err == errNotFound {
+				if sent {
+					return nil
+				}
+				return status.Errorf(codes.NotFound, "cart with ID: %d not found", req.Id)
+			}
+			return errorStatus(err, "failed to get the Cart")
+		}
+
+		// Notifications are coalesced and may come for changes already sent.
+		if !sent || cart.Version != version {
+			resp, err := cartResponse(cart)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+			sent, version = true, cart.Version
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changes:
+		}
+	}
 }
@@ -1,13 +1,18 @@
 package cart
 
 import (
+	"context"
 	"testing"
 	"time"
 
-	"github.com/cooldryplace/proto"
+	"github.com/cooldryplace/cart/proto"
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestToProtoLineItems(t *testing.T) {
@@ -24,12 +29,18 @@ func TestToProtoLineItems(t *testing.T) {
 		{
 			name: "All values",
 			input: LineItem{
-				ProductID: 100500,
-				Quantity:  1,
+				ProductID:     100500,
+				Quantity:      2,
+				UnitPrice:     250,
+				LineTotal:     500,
+				ReservationID: "r-1",
 			},
 			expected: &proto.LineItem{
-				ProductId: 100500,
-				Quantity:  1,
+				ProductId:     100500,
+				Quantity:      2,
+				UnitPrice:     250,
+				LineTotal:     500,
+				ReservationId: "r-1",
 			},
 		},
 	}
@@ -66,27 +77,47 @@ func TestToProtoCart(t *testing.T) {
 			name:  "Zero values",
 			input: Cart{},
 			expected: &proto.Cart{
-				Items:     []*proto.LineItem{},
-				CreatedAt: pZeroTime,
-				UpdatedAt: pZeroTime,
+				Items:      []*proto.LineItem{},
+				SavedItems: []*proto.LineItem{},
+				Discounts:  []*proto.Discount{},
+				CreatedAt:  pZeroTime,
+				UpdatedAt:  pZeroTime,
 			},
 			expectedError: nil,
 		},
 		{
 			name: "All values",
 			input: Cart{
-				ID:        100500,
-				UserID:    42,
-				Items:     lineItems,
-				CreatedAt: createTime,
-				UpdatedAt: updateTime,
+				ID:            100500,
+				UserID:        42,
+				Status:        StatusCheckingOut,
+				Version:       3,
+				Items:         lineItems,
+				SavedItems:    []LineItem{{ProductID: 14, Quantity: 2}},
+				Coupons:       []string{"SAVE5"},
+				Subtotal:      1999,
+				Discounts:     []Discount{{Code: "SAVE5", Description: "5 off", Amount: 5}},
+				DiscountTotal: 5,
+				Total:         1994,
+				Currency:      "USD",
+				CreatedAt:     createTime,
+				UpdatedAt:     updateTime,
 			},
 			expected: &proto.Cart{
-				Id:        100500,
-				UserId:    42,
-				Items:     toProtoLineItems(lineItems),
-				CreatedAt: pCreateTime,
-				UpdatedAt: pUpdateTime,
+				Id:            100500,
+				UserId:        42,
+				Status:        proto.CartStatus_STATUS_CHECKING_OUT,
+				Version:       3,
+				Items:         toProtoLineItems(lineItems),
+				SavedItems:    []*proto.LineItem{{ProductId: 14, Quantity: 2}},
+				Coupons:       []string{"SAVE5"},
+				Subtotal:      1999,
+				Discounts:     []*proto.Discount{{Code: "SAVE5", Description: "5 off", Amount: 5}},
+				DiscountTotal: 5,
+				Total:         1994,
+				Currency:      "USD",
+				CreatedAt:     pCreateTime,
+				UpdatedAt:     pUpdateTime,
 			},
 		},
 	}
@@ -104,3 +135,252 @@ func TestToProtoCart(t *testing.T) {
 		})
 	}
 }
+
+func TestIdempotencyKey(t *testing.T) {
+	withHeader := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyHeader, "header"))
+
+	cases := []struct {
+		name     string
+		ctx      context.Context
+		key      string
+		expected string
+	}{
+		{
+			name:     "No key",
+			ctx:      context.Background(),
+			expected: "",
+		},
+		{
+			name:     "Request field",
+			ctx:      context.Background(),
+			key:      "field",
+			expected: "field",
+		},
+		{
+			name:     "Metadata",
+			ctx:      withHeader,
+			expected: "header",
+		},
+		{
+			name:     "Request field wins",
+			ctx:      withHeader,
+			key:      "field",
+			expected: "field",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := idempotencyKey(c.ctx, c.key); actual != c.expected {
+				t.Errorf("Got key: %q, expected: %q", actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestActorInterceptor(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(actorHeader, "support:42", callerHeader, "7"))
+
+	_, err := NewActorInterceptor(nil)(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if a := actor(ctx); a != "support:42" {
+			t.Errorf("Got actor: %q, expected: %q", a, "support:42")
+		}
+		if id, ok := caller(ctx); !ok || id != 7 {
+			t.Errorf("Got caller: %d, expected: 7", id)
+		}
+		if trusted(ctx) {
+			t.Error("Got trusted call without a service key")
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+}
+
+func TestActorInterceptorInvalidCaller(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(callerHeader, "guest"))
+
+	_, err := NewActorInterceptor(nil)(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		t.Error("Handler called with invalid caller")
+		return nil, nil
+	})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Fatalf("Got code: %s, expected: %s", code, codes.InvalidArgument)
+	}
+}
+
+func TestActorInterceptorServiceKey(t *testing.T) {
+	interceptor := NewActorInterceptor([]string{"", "secret"})
+
+	cases := []struct {
+		name            string
+		md              metadata.MD
+		expectedTrusted bool
+		expectedCode    codes.Code
+	}{
+		{
+			name: "No service key",
+			md:   metadata.Pairs(actorHeader, "support:42"),
+		},
+		{
+			name:            "Valid service key",
+			md:              metadata.Pairs(serviceKeyHeader, "secret"),
+			expectedTrusted: true,
+		},
+		{
+			name:         "Invalid service key",
+			md:           metadata.Pairs(serviceKeyHeader, "guess"),
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "Empty service key",
+			md:           metadata.Pairs(serviceKeyHeader, ""),
+			expectedCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), c.md)
+
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				if trusted(ctx) != c.expectedTrusted {
+					t.Errorf("Got trusted: %t, expected: %t", trusted(ctx), c.expectedTrusted)
+				}
+				return nil, nil
+			})
+			if code := status.Code(err); code != c.expectedCode {
+				t.Fatalf("Got code: %s, expected: %s", code, c.expectedCode)
+			}
+		})
+	}
+}
+
+func TestInviteMemberUnspecifiedRole(t *testing.T) {
+	storage := &StorageMock{
+		AddMemberFunc: func(ctx context.Context, cartID int64, m Member) error {
+			t.Errorf("Got member added with role: %q", m.Role)
+			return nil
+		},
+	}
+
+	req := &proto.InviteMemberRequest{CartId: 1, UserId: 2, Role: proto.MemberRole_MEMBER_ROLE_UNSPECIFIED}
+
+	_, err := NewServer(New(storage)).InviteMember(WithTrustedService(context.Background()), req)
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Fatalf("Got code: %s, expected: %s", code, codes.InvalidArgument)
+	}
+}
+
+// contextStream is a ServerStream that only has a context.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestActorStreamInterceptor(t *testing.T) {
+	interceptor := NewActorStreamInterceptor([]string{"secret"})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(callerHeader, "7", serviceKeyHeader, "secret"))
+
+	err := interceptor(nil, &contextStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(srv interface{}, ss grpc.ServerStream) error {
+		if id, ok := caller(ss.Context()); !ok || id != 7 {
+			t.Errorf("Got caller: %d, expected: 7", id)
+		}
+		if !trusted(ss.Context()) {
+			t.Error("Expected a trusted call")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(serviceKeyHeader, "guess"))
+
+	err = interceptor(nil, &contextStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(srv interface{}, ss grpc.ServerStream) error {
+		t.Error("Handler called with invalid service key")
+		return nil
+	})
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Fatalf("Got code: %s, expected: %s", code, codes.Unauthenticated)
+	}
+}
+
+func TestReadsWithoutCaller(t *testing.T) {
+	storage := &StorageMock{
+		CartByIDFunc: func(ctx context.Context, id int64) (Cart, error) {
+			t.Errorf("Cart: %d read without a caller", id)
+			return Cart{ID: id}, nil
+		},
+		CartsByUserFunc: func(ctx context.Context, userID, beforeID int64, limit int) ([]Cart, error) {
+			t.Errorf("Carts of User: %d listed without a caller", userID)
+			return nil, nil
+		},
+	}
+
+	var (
+		server      = NewServer(New(storage))
+		interceptor = NewActorInterceptor([]string{"secret"})
+		ctx         = metadata.NewIncomingContext(context.Background(), metadata.Pairs(actorHeader, "support:42"))
+	)
+
+	_, err := interceptor(ctx, &proto.CartRequest{Id: 1}, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return server.GetCart(ctx, req.(*proto.CartRequest))
+	})
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Errorf("Got GetCart code: %s, expected: %s", code, codes.Unauthenticated)
+	}
+
+	_, err = interceptor(ctx, &proto.ListCartsRequest{UserId: 1}, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return server.ListCarts(ctx, req.(*proto.ListCartsRequest))
+	})
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Errorf("Got ListCarts code: %s, expected: %s", code, codes.Unauthenticated)
+	}
+}
+
+func TestApplyCartOperationsNotCommittedIsNotStored(t *testing.T) {
+	var released bool
+
+	storage := &StorageMock{
+		ReserveIdempotencyKeyFunc: func(ctx context.Context, key, method string, now time.Time, ttl time.Duration) ([]byte, bool, error) {
+			return nil, true, nil
+		},
+		ApplyOperationsFunc: func(ctx context.Context, cartID int64, ops []Operation) ([]uint32, int, error) {
+			return nil, 0, errNotEnoughQuantity
+		},
+		SaveIdempotentResponseFunc: func(ctx context.Context, key, method string, resp []byte, expiresAt time.Time) error {
+			t.Error("Response of a not committed batch saved")
+			return nil
+		},
+		ReleaseIdempotencyKeyFunc: func(ctx context.Context, key, method string) error {
+			released = true
+			return nil
+		},
+	}
+
+	req := &proto.ApplyCartOperationsRequest{
+		CartId:         1,
+		Operations:     []*proto.CartOperation{{Type: proto.CartOperation_SET, ProductId: 1, Quantity: 1}},
+		IdempotencyKey: "key",
+	}
+
+	resp, err := NewServer(New(storage)).ApplyCartOperations(WithTrustedService(context.Background()), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if resp.Committed || len(resp.Results) != 1 || codes.Code(resp.Results[0].Code) != codes.FailedPrecondition {
+		t.Errorf("Got response: %v, expected a not committed batch failed with: %s", resp, codes.FailedPrecondition)
+	}
+
+	if !released {
+		t.Error("Idempotency key not released after a not committed batch")
+	}
+}
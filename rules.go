@@ -0,0 +1,136 @@
+package cart
+
+import (
+	"context"
+	"fmt"
+)
+
+// Rule names reported in RuleViolations.
+const (
+	RuleMaxLineQuantity = "max_line_quantity"
+	RuleMaxLines        = "max_lines"
+	RuleMaxUnits        = "max_units"
+	RuleMinQuantity     = "min_quantity"
+	RuleQuantityStep    = "quantity_step"
+)
+
+// Rules limit content of Carts. Zero values mean there is no limit.
+type Rules struct {
+	// MaxLineQuantity limits quantity of a single LineItem.
+	MaxLineQuantity uint32 `json:"maxLineQuantity"`
+	// MaxLines limits number of distinct LineItems in a Cart.
+	MaxLines int `json:"maxLines"`
+	// MaxUnits limits total quantity of all LineItems in a Cart.
+	MaxUnits uint64 `json:"maxUnits"`
+	// Products have their own quantity rules, e.g. eggs are sold by the dozen.
+	Products map[int64]ProductRules `json:"products"`
+}
+
+// ProductRules define allowed quantities of a Product. Quantity of its LineItems must be at least
+// MinQuantity and a multiple of Step.
+type ProductRules struct {
+	MinQuantity uint32 `json:"minQuantity"`
+	Step        uint32 `json:"step"`
+}
+
+// DefaultRules are used by the service unless other Rules are configured.
+var DefaultRules = Rules{
+	MaxLineQuantity: 999,
+	MaxLines:        100,
+	MaxUnits:        9999,
+}
+
+// WithRules sets Rules enforced by all Cart mutations.
+func WithRules(r Rules) Option {
+	return func(c *Carts) {
+		c.rules = &r
+	}
+}
+
+// enforceRules returns a context that makes Storage check the Rules of Carts, if there are any.
+func (c *Carts) enforceRules(ctx context.Context) context.Context {
+	if c.rules == nil {
+		return ctx
+	}
+
+	return withRules(ctx, c.rules)
+}
+
+// RuleViolation describes a Rule the Cart would break. ProductID is zero for Rules of the whole Cart.
+type RuleViolation struct {
+	Rule        string
+	ProductID   int64
+	Description string
+}
+
+func (v *RuleViolation) Error() string {
+	return v.Description
+}
+
+// lineRule reports whether the Rule is about quantity of a single LineItem rather than content of the whole Cart.
+func (v *RuleViolation) lineRule() bool {
+	return v.ProductID != 0
+}
+
+// checkLines of the Product, or all LineItems when productID is zero, against quantity Rules.
+func (r *Rules) checkLines(items []LineItem, productID int64) error {
+	for _, li := range items {
+		if productID != 0 && li.ProductID != productID {
+			continue
+		}
+
+		if r.MaxLineQuantity != 0 && li.Quantity > r.MaxLineQuantity {
+			return &RuleViolation{
+				Rule:        RuleMaxLineQuantity,
+				ProductID:   li.ProductID,
+				Description: fmt.Sprintf("quantity of the product: %d can not exceed %d", li.ProductID, r.MaxLineQuantity),
+			}
+		}
+
+		pr := r.Products[li.ProductID]
+
+		if li.Quantity < pr.MinQuantity {
+			return &RuleViolation{
+				Rule:        RuleMinQuantity,
+				ProductID:   li.ProductID,
+				Description: fmt.Sprintf("quantity of the product: %d must be at least %d", li.ProductID, pr.MinQuantity),
+			}
+		}
+
+		if pr.Step > 1 && li.Quantity%pr.Step != 0 {
+			return &RuleViolation{
+				Rule:        RuleQuantityStep,
+				ProductID:   li.ProductID,
+				Description: fmt.Sprintf("quantity of the product: %d must be a multiple of %d", li.ProductID, pr.Step),
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkTotals of the Cart LineItems against Rules of the whole Cart.
+func (r *Rules) checkTotals(items []LineItem) error {
+	if r.MaxLines != 0 && len(items) > r.MaxLines {
+		return &RuleViolation{
+			Rule:        RuleMaxLines,
+			Description: fmt.Sprintf("cart can not have more than %d items", r.MaxLines),
+		}
+	}
+
+	if r.MaxUnits != 0 {
+		var units uint64
+		for _, li := range items {
+			units += uint64(li.Quantity)
+		}
+
+		if units > r.MaxUnits {
+			return &RuleViolation{
+				Rule:        RuleMaxUnits,
+				Description: fmt.Sprintf("cart can not have more than %d units", r.MaxUnits),
+			}
+		}
+	}
+
+	return nil
+}
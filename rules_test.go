@@ -0,0 +1,151 @@
+package cart
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCartRules(t *testing.T) {
+	rules := Rules{
+		MaxLineQuantity: 24,
+		MaxLines:        2,
+		MaxUnits:        30,
+		Products:        map[int64]ProductRules{1: {MinQuantity: 6, Step: 6}},
+	}
+
+	cases := []struct {
+		name          string
+		items         []LineItem
+		productID     int64
+		expectedRule  string
+		expectedTotal string
+	}{
+		{
+			name:  "Allowed quantities",
+			items: []LineItem{{ProductID: 1, Quantity: 12}, {ProductID: 2, Quantity: 1}},
+		},
+		{
+			name:         "Line quantity is too large",
+			items:        []LineItem{{ProductID: 2, Quantity: 25}},
+			expectedRule: RuleMaxLineQuantity,
+		},
+		{
+			name:         "Less than minimum quantity",
+			items:        []LineItem{{ProductID: 1, Quantity: 1}},
+			expectedRule: RuleMinQuantity,
+		},
+		{
+			name:         "Not a multiple of the step",
+			items:        []LineItem{{ProductID: 1, Quantity: 8}},
+			expectedRule: RuleQuantityStep,
+		},
+		{
+			name:      "Only lines of the Product are checked",
+			items:     []LineItem{{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 1}},
+			productID: 2,
+		},
+		{
+			name:          "Too many lines",
+			items:         []LineItem{{ProductID: 2, Quantity: 1}, {ProductID: 3, Quantity: 1}, {ProductID: 4, Quantity: 1}},
+			expectedTotal: RuleMaxLines,
+		},
+		{
+			name:          "Too many units",
+			items:         []LineItem{{ProductID: 2, Quantity: 20}, {ProductID: 3, Quantity: 20}},
+			expectedTotal: RuleMaxUnits,
+		},
+	}
+
+	rule := func(err error) string {
+		if err == nil {
+			return ""
+		}
+		return err.(*RuleViolation).Rule
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := rule(rules.checkLines(c.items, c.productID)); actual != c.expectedRule {
+				t.Errorf("Got line rule violation: %q, expected: %q", actual, c.expectedRule)
+			}
+
+			if actual := rule(rules.checkTotals(c.items)); actual != c.expectedTotal {
+				t.Errorf("Got total rule violation: %q, expected: %q", actual, c.expectedTotal)
+			}
+		})
+	}
+}
+
+func TestNoCartRules(t *testing.T) {
+	var rules Rules
+
+	items := []LineItem{{ProductID: 1, Quantity: maxQuantity}, {ProductID: 2, Quantity: 1}}
+
+	if err := rules.checkLines(items, 0); err != nil {
+		t.Errorf("Got error: %s, expected no line limits", err)
+	}
+	if err := rules.checkTotals(items); err != nil {
+		t.Errorf("Got error: %s, expected no total limits", err)
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	cases := []struct {
+		name            string
+		err             error
+		expectedCode    codes.Code
+		expectedDetails []interface{}
+	}{
+		{
+			name:         "Not a rule violation",
+			err:          errCartNotOpen,
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "Line rule",
+			err:          &RuleViolation{Rule: RuleQuantityStep, ProductID: 1, Description: "quantity of the product: 1 must be a multiple of 6"},
+			expectedCode: codes.InvalidArgument,
+			expectedDetails: []interface{}{&errdetails.BadRequest{
+				FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+					Field:       "quantity[productId=1]",
+					Description: "quantity_step: quantity of the product: 1 must be a multiple of 6",
+				}},
+			}},
+		},
+		{
+			name:         "Cart rule",
+			err:          &RuleViolation{Rule: RuleMaxLines, Description: "cart can not have more than 2 items"},
+			expectedCode: codes.FailedPrecondition,
+			expectedDetails: []interface{}{&errdetails.PreconditionFailure{
+				Violations: []*errdetails.PreconditionFailure_Violation{{
+					Type:        RuleMaxLines,
+					Subject:     "cart",
+					Description: "cart can not have more than 2 items",
+				}},
+			}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			st := status.Convert(errorStatus(c.err, "failed"))
+
+			if st.Code() != c.expectedCode {
+				t.Errorf("Got code: %s, expected: %s", st.Code(), c.expectedCode)
+			}
+
+			if msg := "failed: " + c.err.Error(); st.Message() != msg {
+				t.Errorf("Got message: %q, expected: %q", st.Message(), msg)
+			}
+
+			if diff := cmp.Diff(c.expectedDetails, st.Details(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Details mismatch (-expected +got):\n%s", diff)
+			}
+		})
+	}
+}
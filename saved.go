@@ -0,0 +1,57 @@
+package cart
+
+import (
+	"context"
+	"log"
+)
+
+// SaveForLater moves the Product with Options from Cart items to its SavedItems, keeping quantity and timestamps.
+// Quantities are combined when the LineItem is already saved. Returns the priced Cart.
+func (c *Carts) SaveForLater(ctx context.Context, cartID, productID int64, options Options) (Cart, error) {
+	if err := c.authorize(ctx, cartID, RoleEditor); err != nil {
+		return Cart{}, err
+	}
+
+	options, err := options.normalize()
+	if err != nil {
+		return Cart{}, err
+	}
+
+	if err := c.storage.SaveForLater(ctx, cartID, productID, options); err != nil {
+		log.Printf("Failed to save the Product: %d of the Cart: %d for later, error: %s", productID, cartID, err)
+		return Cart{}, err
+	}
+
+	c.changes.notify(cartID)
+
+	return c.Cart(ctx, cartID)
+}
+
+// MoveToCart moves the Product with Options from SavedItems of the Cart back to its items, keeping quantity and timestamps.
+// Quantities are combined when the LineItem is already in the Cart. Product must be orderable according
+// to the ProductCatalog, if Carts have one. Returns the priced Cart.
+func (c *Carts) MoveToCart(ctx context.Context, cartID, productID int64, options Options) (Cart, error) {
+	if err := c.authorize(ctx, cartID, RoleEditor); err != nil {
+		return Cart{}, err
+	}
+
+	options, err := options.normalize()
+	if err != nil {
+		return Cart{}, err
+	}
+
+	limit, err := c.orderable(ctx, productID)
+	if err != nil {
+		log.Printf("Failed to validate the Product: %d, error: %s", productID, err)
+		return Cart{}, err
+	}
+
+	if err := c.storage.MoveToCart(c.enforceRules(ctx), cartID, productID, options, limit); err != nil {
+		log.Printf("Failed to move the Product: %d to the Cart: %d, error: %s", productID, cartID, err)
+		return Cart{}, err
+	}
+
+	c.changes.notify(cartID)
+
+	return c.Cart(ctx, cartID)
+}
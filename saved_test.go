@@ -0,0 +1,59 @@
+package cart
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMoveToCart(t *testing.T) {
+	catalog := StaticCatalog{
+		1: {ID: 1, Active: true, MaxQuantity: 3},
+		2: {ID: 2},
+	}
+
+	cases := []struct {
+		name          string
+		productID     int64
+		expectedLimit uint32
+		expectedError error
+	}{
+		{
+			name:          "Limited quantity",
+			productID:     1,
+			expectedLimit: 3,
+		},
+		{
+			name:          "Discontinued",
+			productID:     2,
+			expectedError: errProductInactive,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var moved bool
+
+			storage := &StorageMock{
+				MoveToCartFunc: func(ctx context.Context, cartID, productID int64, options Options, limit uint32) error {
+					moved = true
+					if limit != c.expectedLimit {
+						t.Errorf("Got limit: %d, expected: %d", limit, c.expectedLimit)
+					}
+					return nil
+				},
+				CartByIDFunc: func(ctx context.Context, id int64) (Cart, error) {
+					return Cart{ID: id}, nil
+				},
+			}
+
+			_, err := New(storage, WithCatalog(catalog)).MoveToCart(WithTrustedService(context.Background()), 1, c.productID, nil)
+			if err != c.expectedError {
+				t.Fatalf("Got error: %v, expected: %v", err, c.expectedError)
+			}
+
+			if moved != (c.expectedError == nil) {
+				t.Errorf("Got moved: %t, expected: %t", moved, c.expectedError == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package cart
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+var errSnapshotNotFound = errors.New("snapshot not found")
+
+// Snapshot is an immutable copy of a Cart, for example, the one an order is placed for.
+// Hash identifies the content of the Snapshot: LineItems, coupons and prices.
+type Snapshot struct {
+	ID        int64
+	Cart      Cart
+	Hash      string
+	CreatedAt time.Time
+}
+
+// snapshotItem is a part of the hashed Snapshot content.
+type snapshotItem struct {
+	ProductID int64   `json:"productId"`
+	Options   Options `json:"options,omitempty"`
+	Note      string  `json:"note,omitempty"`
+	Quantity  uint32  `json:"quantity"`
+	UnitPrice int64   `json:"unitPrice"`
+	LineTotal int64   `json:"lineTotal"`
+}
+
+// snapshotHash returns SHA-256 of the Cart content. Carts with the same LineItems, prices and coupons have the same hash.
+func snapshotHash(cart Cart) (string, error) {
+	content := struct {
+		Items         []snapshotItem `json:"items"`
+		Coupons       []string       `json:"coupons"`
+		Discounts     []Discount     `json:"discounts"`
+		Subtotal      int64          `json:"subtotal"`
+		DiscountTotal int64          `json:"discountTotal"`
+		Total         int64          `json:"total"`
+		Currency      string         `json:"currency"`
+	}{
+		Items:         make([]snapshotItem, 0, len(cart.Items)),
+		Coupons:       cart.Coupons,
+		Discounts:     cart.Discounts,
+		Subtotal:      cart.Subtotal,
+		DiscountTotal: cart.DiscountTotal,
+		Total:         cart.Total,
+		Currency:      cart.Currency,
+	}
+
+	for _, li := range cart.Items {
+		content.Items = append(content.Items, snapshotItem{
+			ProductID: li.ProductID,
+			Options:   li.Options,
+			Note:      li.Note,
+			Quantity:  li.Quantity,
+			UnitPrice: li.UnitPrice,
+			LineTotal: li.LineTotal,
+		})
+	}
+
+	b, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the Snapshot content: %s", err)
+	}
+
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Snapshot freezes the current state of a Cart, priced when Carts have a PriceProvider. All LineItems must be priced.
+// It fails with errVersionMismatch when the Cart is changed while the Snapshot is taken. Only members can snapshot the Cart.
+func (c *Carts) Snapshot(ctx context.Context, cartID int64) (Snapshot, error) {
+	if err := c.authorize(ctx, cartID, RoleViewer); err != nil {
+		return Snapshot{}, err
+	}
+
+	cart, err := c.cart(ctx, cartID, false)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	hash, err := snapshotHash(cart)
+	if err != nil {
+		log.Printf("Failed to hash the Cart: %d, error: %s", cartID, err)
+		return Snapshot{}, err
+	}
+
+	snapshot, err := c.storage.CreateSnapshot(ctx, Snapshot{Cart: cart, Hash: hash, CreatedAt: time.Now()})
+	if err != nil {
+		log.Printf("Failed to save a Snapshot of the Cart: %d, error: %s", cartID, err)
+		return Snapshot{}, err
+	}
+
+	return snapshot, nil
+}
+
+// GetSnapshot by ID. Snapshots are kept after their Carts are deleted. Only the owner and members of the Snapshot Cart
+// can get the Snapshot, the owner can get it even after the Cart is purged.
+func (c *Carts) GetSnapshot(ctx context.Context, id int64) (Snapshot, error) {
+	snapshot, err := c.storage.Snapshot(ctx, id)
+	if err != nil {
+		if err != errSnapshotNotFound {
+			log.Printf("Failed to get the Snapshot: %d, error: %s", id, err)
+		}
+		return Snapshot{}, err
+	}
+
+	if err := authorizeUser(ctx, snapshot.Cart.UserID); err != nil {
+		if err := c.authorize(ctx, snapshot.Cart.ID, RoleViewer); err != nil {
+			return Snapshot{}, err
+		}
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,69 @@
+package cart
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSnapshotHash(t *testing.T) {
+	cart := Cart{
+		ID:       1,
+		Version:  3,
+		Items:    []LineItem{{ProductID: 7, Quantity: 2, UnitPrice: 100, LineTotal: 200}},
+		Subtotal: 200,
+		Total:    200,
+		Currency: "USD",
+	}
+
+	hash, err := snapshotHash(cart)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	same := cart
+	same.ID, same.Version, same.UpdatedAt = 2, 5, time.Now()
+
+	if h, _ := snapshotHash(same); h != hash {
+		t.Errorf("Got hash: %s, expected: %s for the same content", h, hash)
+	}
+
+	changed := cart
+	changed.Items = []LineItem{{ProductID: 7, Quantity: 3, UnitPrice: 100, LineTotal: 300}}
+
+	if h, _ := snapshotHash(changed); h == hash {
+		t.Error("Expected different hash for different content")
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	var (
+		ctx          = WithTrustedService(context.Background())
+		cartID int64 = 1
+		cart         = Cart{ID: cartID, Version: 4, Items: []LineItem{{ProductID: 7, Quantity: 2}}}
+	)
+
+	storage := &StorageMock{
+		CartByIDFunc: func(ctx context.Context, id int64) (Cart, error) {
+			return cart, nil
+		},
+		CreateSnapshotFunc: func(ctx context.Context, snapshot Snapshot) (Snapshot, error) {
+			if snapshot.Cart.Version != cart.Version {
+				t.Errorf("Got Cart version: %d, expected: %d", snapshot.Cart.Version, cart.Version)
+			}
+			snapshot.ID = 10
+			return snapshot, nil
+		},
+	}
+
+	snapshot, err := New(storage).Snapshot(ctx, cartID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expectedHash, _ := snapshotHash(cart)
+
+	if snapshot.ID != 10 || snapshot.Hash != expectedHash {
+		t.Errorf("Got Snapshot: %d with hash: %s, expected: 10 with hash: %s", snapshot.ID, snapshot.Hash, expectedHash)
+	}
+}
@@ -3,24 +3,121 @@ package cart
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
+	"math"
+	"strconv"
 	"time"
+
+	"github.com/lib/pq"
 )
 
+// maxQuantity of a single LineItem, line_items.quantity column is INTEGER.
+const maxQuantity = math.MaxInt32
+
 const (
-	sqlCreateCart   = `INSERT INTO carts (user_id, created_at, updated_at) VALUES ($1, $2, $3) RETURNING cart_id`
-	sqlDeleteCart   = `DELETE FROM carts WHERE cart_id = $1`
-	sqlCartByID     = `SELECT user_id, created_at, updated_at FROM carts WHERE cart_id = $1`
-	sqlUpdateCartTS = `UPDATE carts SET updated_at = $2 WHERE cart_id = $1`
-
-	sqlLinesByCartID   = `SELECT product_id, quantity FROM line_items WHERE cart_id = $1`
-	sqlProductQuantity = `SELECT quantity FROM line_items WHERE cart_id = $1 AND product_id = $2`
-
-	sqlCreateLineItem  = `INSERT INTO line_items (cart_id, product_id, quantity, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`
-	sqlUpdateLineItem  = `UPDATE line_items SET quantity = $3, updated_at = $4 WHERE cart_id = $1 AND product_id = $2`
-	sqlDeleteLineItem  = `DELETE FROM line_items WHERE cart_id = $1 AND product_id = $2`
-	sqlDeleteLineItems = `DELETE FROM line_items WHERE cart_id = $1`
+	sqlCreateCart   = `INSERT INTO carts (user_id, status, created_at, updated_at) VALUES ($1, $2, $3, $4) RETURNING cart_id, version`
+	sqlCartByID     = `SELECT user_id, status, version, created_at, updated_at FROM carts WHERE cart_id = $1 AND deleted_at IS NULL`
+	sqlUpdateCartTS = `UPDATE carts SET updated_at = $2, version = version + 1 WHERE cart_id = $1`
+	sqlUpdateStatus = `UPDATE carts SET status = $2, updated_at = $3, version = version + 1 WHERE cart_id = $1`
+	sqlLockCart     = `SELECT status, version FROM carts WHERE cart_id = $1 AND deleted_at IS NULL FOR UPDATE`
+	sqlCartsByUser  = `SELECT cart_id, status, version, created_at, updated_at FROM carts
+		WHERE user_id = $1 AND deleted_at IS NULL AND ($2 = 0 OR cart_id < $2) ORDER BY cart_id DESC LIMIT $3`
+	sqlActiveCartID = `SELECT cart_id FROM carts WHERE user_id = $1 AND status = 'open' AND deleted_at IS NULL ORDER BY updated_at DESC, cart_id DESC LIMIT 1`
+
+	// User locks serialize opening of Carts of a User. They use their own namespace of advisory locks,
+	// User IDs are hashed to fit the second key.
+	sqlLockUser     = `SELECT pg_advisory_xact_lock($1, hashint8($2))`
+	sqlLockCartUser = `SELECT pg_advisory_xact_lock($1, hashint8(user_id)) FROM carts WHERE cart_id = $2`
+
+	// Soft-deleted Carts keep their content until they are purged, they can be restored meanwhile.
+	sqlSoftDeleteCart = `UPDATE carts SET deleted_at = $2, version = version + 1 WHERE cart_id = $1 AND deleted_at IS NULL`
+	sqlLockDeleted    = `SELECT deleted_at FROM carts WHERE cart_id = $1 FOR UPDATE`
+	sqlRestoreCart    = `UPDATE carts SET deleted_at = NULL, updated_at = $2, version = version + 1 WHERE cart_id = $1`
+
+	// Guests have non-positive User IDs. Carts in checkout hold stock reservations and are never expired.
+	// Soft-deleted Carts are purged after retention regardless of their status.
+	sqlExpiredCarts = `SELECT cart_id, deleted_at IS NOT NULL FROM carts WHERE deleted_at < $3 OR (deleted_at IS NULL AND status <> 'checking_out'
+		AND updated_at < CASE WHEN user_id > 0 THEN $2 ELSE $1 END::TIMESTAMP) ORDER BY updated_at LIMIT $4 FOR UPDATE SKIP LOCKED`
+	sqlDeleteCartsLineItems = `DELETE FROM line_items WHERE cart_id = ANY($1)`
+	sqlDeleteCartsCoupons   = `DELETE FROM cart_coupons WHERE cart_id = ANY($1)`
+	sqlDeleteCartsMembers   = `DELETE FROM cart_members WHERE cart_id = ANY($1)`
+	sqlDeleteCarts          = `DELETE FROM carts WHERE cart_id = ANY($1)`
+
+	// Carts are abandoned once per idle period, any change of the Cart moves updated_at past abandoned_at.
+	sqlIdleCarts = `SELECT cart_id, user_id FROM carts WHERE status = 'open' AND deleted_at IS NULL AND updated_at < $1
+		AND (abandoned_at IS NULL OR abandoned_at < updated_at)
+		AND EXISTS (SELECT 1 FROM line_items WHERE line_items.cart_id = carts.cart_id AND NOT saved)
+		ORDER BY updated_at LIMIT $2 FOR UPDATE SKIP LOCKED`
+	sqlMarkAbandoned = `UPDATE carts SET abandoned_at = $2 WHERE cart_id = ANY($1)`
+
+	// LineItems are identified by Product and options_key, a canonical representation of normalized Options.
+	sqlLinesByCartID = `SELECT product_id, quantity, options, note, COALESCE(reservation_id, ''), reserved_until FROM line_items
+		WHERE cart_id = $1 AND NOT saved ORDER BY item_id`
+	sqlLineItem = `SELECT quantity, options, note FROM line_items WHERE cart_id = $1 AND product_id = $2 AND options_key = $3 AND NOT saved`
+
+	sqlCreateLineItem = `INSERT INTO line_items (cart_id, product_id, options, options_key, note, quantity, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	sqlUpdateLineItem = `UPDATE line_items SET quantity = $4, note = $5, updated_at = $6 WHERE cart_id = $1 AND product_id = $2 AND options_key = $3 AND NOT saved`
+	sqlDeleteLineItem = `DELETE FROM line_items WHERE cart_id = $1 AND product_id = $2 AND options_key = $3 AND NOT saved`
+	sqlEmptyCart      = `DELETE FROM line_items WHERE cart_id = $1 AND NOT saved`
+
+	sqlSavedByCartID = `SELECT product_id, quantity, options, note, created_at, updated_at FROM line_items WHERE cart_id = $1 AND saved ORDER BY item_id`
+	sqlListedItem    = `SELECT quantity FROM line_items WHERE cart_id = $1 AND product_id = $2 AND options_key = $3 AND saved = $4`
+	sqlUpdateListed  = `UPDATE line_items SET quantity = $5, updated_at = $6 WHERE cart_id = $1 AND product_id = $2 AND options_key = $3 AND saved = $4`
+	sqlDeleteListed  = `DELETE FROM line_items WHERE cart_id = $1 AND product_id = $2 AND options_key = $3 AND saved = $4`
+	sqlMoveLineItem  = `UPDATE line_items SET saved = $4 WHERE cart_id = $1 AND product_id = $2 AND options_key = $3 AND saved <> $4`
+	// Saved items of the source Cart not saved in the target Cart are kept on merge.
+	sqlMergeSaved = `INSERT INTO line_items (cart_id, product_id, options, options_key, note, quantity, saved, created_at, updated_at)
+		SELECT $2, product_id, options, options_key, note, quantity, TRUE, $3, $3 FROM line_items WHERE cart_id = $1 AND saved
+		AND (product_id, options_key) NOT IN (SELECT product_id, options_key FROM line_items WHERE cart_id = $2 AND saved)`
+
+	sqlReservations      = `SELECT product_id, reservation_id, reserved_until FROM line_items WHERE cart_id = $1 AND reservation_id IS NOT NULL`
+	sqlUpdateReservation = `UPDATE line_items SET reservation_id = $3, reserved_until = $4 WHERE cart_id = $1 AND product_id = $2 AND NOT saved`
+
+	// Reservations of ordered Carts are kept for the Order.
+	sqlExpiredReservations = `SELECT li.cart_id, li.product_id, li.reservation_id, li.reserved_until FROM line_items li
+		JOIN carts c ON c.cart_id = li.cart_id
+		WHERE li.reserved_until <= $1 AND c.status = 'checking_out' AND c.deleted_at IS NULL ORDER BY li.reserved_until LIMIT $2`
+
+	sqlCouponsByCartID = `SELECT code FROM cart_coupons WHERE cart_id = $1 ORDER BY created_at, code`
+	sqlCreateCoupon    = `INSERT INTO cart_coupons (cart_id, code, created_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+	sqlDeleteCoupon    = `DELETE FROM cart_coupons WHERE cart_id = $1 AND code = $2`
+
+	// Owner of the Cart is its User, other members are listed in cart_members. Role of deleted Carts is kept for restore.
+	sqlMemberRole = `SELECT CASE WHEN carts.user_id = $2 THEN 'owner' ELSE COALESCE(cart_members.role, '') END FROM carts
+		LEFT JOIN cart_members ON cart_members.cart_id = carts.cart_id AND cart_members.user_id = $2 WHERE carts.cart_id = $1`
+	sqlCartOwner = `SELECT user_id, created_at FROM carts WHERE cart_id = $1 AND deleted_at IS NULL`
+	sqlLockOwner = `SELECT user_id FROM carts WHERE cart_id = $1 AND deleted_at IS NULL FOR UPDATE`
+	sqlMembers   = `SELECT user_id, role, created_at FROM cart_members WHERE cart_id = $1 ORDER BY created_at, user_id`
+	sqlAddMember = `INSERT INTO cart_members (cart_id, user_id, role, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (cart_id, user_id) DO UPDATE SET role = EXCLUDED.role`
+	sqlRemoveMember = `DELETE FROM cart_members WHERE cart_id = $1 AND user_id = $2`
+
+	sqlCreateEvent   = `INSERT INTO outbox (event_type, cart_id, payload, created_at) VALUES ($1, $2, $3, $4)`
+	sqlPendingEvents = `SELECT event_id, payload FROM outbox WHERE published_at IS NULL ORDER BY event_id LIMIT $1 FOR UPDATE SKIP LOCKED`
+	sqlPublishEvents = `UPDATE outbox SET published_at = $2 WHERE event_id = ANY($1)`
+	sqlDeleteEvents  = `DELETE FROM outbox WHERE event_id IN (SELECT event_id FROM outbox WHERE published_at < $1 ORDER BY published_at LIMIT $2)`
+	sqlNotifyChange  = `SELECT pg_notify($1, $2)`
+
+	sqlCreateHistoryEntry = `INSERT INTO cart_events (cart_id, actor, operation, product_id, old_quantity, new_quantity, detail, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	sqlHistory = `SELECT event_id, actor, operation, product_id, old_quantity, new_quantity, detail, created_at FROM cart_events
+		WHERE cart_id = $1 AND ($2 = 0 OR event_id < $2) ORDER BY event_id DESC LIMIT $3`
+
+	sqlCreateSnapshot = `INSERT INTO cart_snapshots (cart_id, cart_version, content, hash, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING snapshot_id`
+	sqlSnapshotByID   = `SELECT content, hash, created_at FROM cart_snapshots WHERE snapshot_id = $1`
+
+	// Expired keys are reused as if they did not exist.
+	sqlReserveIdempotencyKey = `INSERT INTO idempotency_keys (idempotency_key, method, caller, created_at, expires_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (idempotency_key, method, caller) DO UPDATE SET response = NULL, created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at`
+	sqlIdempotentResponse       = `SELECT response IS NOT NULL, COALESCE(response, ''::BYTEA) FROM idempotency_keys WHERE idempotency_key = $1 AND method = $2 AND caller = $3`
+	sqlSaveIdempotentResponse   = `UPDATE idempotency_keys SET response = $4, expires_at = $5 WHERE idempotency_key = $1 AND method = $2 AND caller = $3`
+	sqlDeleteIdempotencyKey     = `DELETE FROM idempotency_keys WHERE idempotency_key = $1 AND method = $2 AND caller = $3`
+	sqlDeleteExpiredIdempotency = `DELETE FROM idempotency_keys WHERE (idempotency_key, method, caller) IN
+		(SELECT idempotency_key, method, caller FROM idempotency_keys WHERE expires_at <= $1 ORDER BY expires_at LIMIT $2)`
 )
 
 type Storage struct {
@@ -33,233 +130,1680 @@ func NewStorage(db *sql.DB) *Storage {
 
 var readOnly = &sql.TxOptions{ReadOnly: true}
 
-func lineItem(ctx context.Context, tx *sql.Tx, cartID, productID int64) (LineItem, error) {
-	li := LineItem{
-		ProductID: productID,
-	}
+// lineItem returns the LineItem of the Product with normalized Options.
+func lineItem(ctx context.Context, tx *sql.Tx, cartID, productID int64, options Options) (LineItem, error) {
+	var (
+		li = LineItem{
+			ProductID: productID,
+		}
+		encoded []byte
+	)
 
-	row := tx.QueryRowContext(ctx, sqlProductQuantity, cartID, productID)
-	if err := row.Scan(&li.Quantity); err != nil {
+	row := tx.QueryRowContext(ctx, sqlLineItem, cartID, productID, options.key())
+	if err := row.Scan(&li.Quantity, &encoded, &li.Note); err != nil {
 		if err == sql.ErrNoRows {
 			return LineItem{}, errNotFound
 		}
 		return LineItem{}, err
 	}
 
+	var err error
+	if li.Options, err = decodeOptions(encoded); err != nil {
+		return LineItem{}, err
+	}
+
 	return li, nil
 }
 
+// userLocks is the namespace of advisory locks of Users.
+const userLocks int32 = 0x63617274
+
+// lockUser prevents concurrent opening of Carts of the User until the end of transaction, so GetOrCreateCart
+// does not create a second open Cart.
+func lockUser(ctx context.Context, tx *sql.Tx, userID int64) error {
+	_, err := tx.ExecContext(ctx, sqlLockUser, userLocks, userID)
+	return err
+}
+
+// lockCartUser locks the User of the Cart as lockUser does.
+func lockCartUser(ctx context.Context, tx *sql.Tx, cartID int64) error {
+	_, err := tx.ExecContext(ctx, sqlLockCartUser, userLocks, cartID)
+	return err
+}
+
+// lockCart prevents concurrent modifications of the Cart until the end of transaction
+// and returns its Status. Zero expected version matches any Cart version.
+func lockCart(ctx context.Context, tx *sql.Tx, cartID, expected int64) (Status, error) {
+	var (
+		status  Status
+		version int64
+	)
+
+	if err := tx.QueryRowContext(ctx, sqlLockCart, cartID).Scan(&status, &version); err != nil {
+		if err == sql.ErrNoRows {
+			return "", errNotFound
+		}
+		return "", err
+	}
+
+	if expected != 0 && expected != version {
+		return "", errVersionMismatch
+	}
+
+	return status, nil
+}
+
+// lockOpenCart locks the Cart for modification. Only open Carts of the version expected by the caller can be modified.
+func lockOpenCart(ctx context.Context, tx *sql.Tx, cartID int64) error {
+	status, err := lockCart(ctx, tx, cartID, expectedVersion(ctx))
+	if err != nil {
+		return err
+	}
+
+	if status != StatusOpen {
+		return errCartNotOpen
+	}
+
+	return nil
+}
+
 func createLineItem(ctx context.Context, tx *sql.Tx, cartID int64, li LineItem) error {
-	_, err := tx.ExecContext(ctx, sqlCreateLineItem, cartID, li.ProductID, li.Quantity, li.CreatedAt, li.UpdatedAt)
+	options, err := encodeOptions(li.Options)
+	if err != nil {
+		return fmt.Errorf("failed to marshal LineItem options: %s", err)
+	}
+
+	_, err = tx.ExecContext(ctx, sqlCreateLineItem,
+		cartID, li.ProductID, options, li.Options.key(), li.Note, li.Quantity, li.CreatedAt, li.UpdatedAt)
 	return err
 }
 
 func updateLineItem(ctx context.Context, tx *sql.Tx, cartID int64, li LineItem) error {
-	_, err := tx.ExecContext(ctx, sqlUpdateLineItem, cartID, li.ProductID, li.Quantity, li.UpdatedAt)
+	_, err := tx.ExecContext(ctx, sqlUpdateLineItem, cartID, li.ProductID, li.Options.key(), li.Quantity, li.Note, li.UpdatedAt)
 	return err
 }
 
-func (s *Storage) AddProduct(ctx context.Context, cartID, productID int64, quantity uint32) error {
-	tx, err := s.db.BeginTx(ctx, nil)
+func touchCart(ctx context.Context, tx *sql.Tx, cartID int64, ts time.Time) error {
+	_, err := tx.ExecContext(ctx, sqlUpdateCartTS, cartID, ts)
+	return err
+}
+
+// writeEvent to the outbox. It must be called in the transaction that makes the change.
+// Change listeners of other instances are notified when the transaction is committed.
+func writeEvent(ctx context.Context, tx *sql.Tx, e Event) error {
+	payload, err := json.Marshal(e)
 	if err != nil {
-		return fmt.Errorf("failed to start transaction: %s", err)
+		return fmt.Errorf("failed to marshal the Event: %s", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, sqlCreateEvent, e.Type, e.CartID, payload, e.CreatedAt); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, sqlNotifyChange, changesChannel, strconv.FormatInt(e.CartID, 10))
+	return err
+}
+
+// writeHistory appends the change to the Cart history. Actor of the change is taken from the context.
+func writeHistory(ctx context.Context, tx *sql.Tx, h HistoryEntry) error {
+	_, err := tx.ExecContext(ctx, sqlCreateHistoryEntry,
+		h.CartID, actor(ctx), h.Operation, h.ProductID, h.OldQuantity, h.NewQuantity, h.Detail, h.CreatedAt)
+	return err
+}
+
+// recordChange of the Cart content. The Cart is touched and the Event is written to the outbox.
+func recordChange(ctx context.Context, tx *sql.Tx, e Event) error {
+	if err := touchCart(ctx, tx, e.CartID, e.CreatedAt); err != nil {
+		return err
 	}
 
-	exists := true
+	return writeEvent(ctx, tx, e)
+}
+
+// checkRules of the Cart from the context. Quantity Rules are checked for LineItems of the Product,
+// or all LineItems when productID is zero, totals of the Cart are checked only when requested.
+func checkRules(ctx context.Context, tx *sql.Tx, cartID, productID int64, totals bool) error {
+	r := rulesFrom(ctx)
+	if r == nil {
+		return nil
+	}
 
-	li, err := lineItem(ctx, tx, cartID, productID)
+	items, err := lineItems(ctx, tx, cartID)
 	if err != nil {
-		if err == errNotFound {
-			exists = false
-		} else {
-			if err := tx.Rollback(); err != nil {
-				log.Printf("Rollback failed: %s", err)
-			}
-			return err
+		return err
+	}
+
+	if err := r.checkLines(items, productID); err != nil {
+		return err
+	}
+
+	if totals {
+		return r.checkTotals(items)
+	}
+
+	return nil
+}
+
+// withTx runs f in a transaction. Transaction is committed when f succeeds and rolled back otherwise.
+func (s *Storage) withTx(ctx context.Context, opts *sql.TxOptions, f func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %s", err)
+	}
+
+	if err := f(tx); err != nil {
+		if err := tx.Rollback(); err != nil {
+			log.Printf("Rollback failed: %s", err)
 		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %s", err)
+	}
+
+	return nil
+}
+
+// addLineItem increases quantity of the Product with normalized Options in the Cart. Non-empty note replaces
+// the one of the LineItem. Resulting quantity can not exceed the limit, zero limit means maxQuantity.
+func addLineItem(ctx context.Context, tx *sql.Tx, cartID, productID int64, options Options, quantity uint32, note string, limit uint32) error {
+	if limit == 0 || limit > maxQuantity {
+		limit = maxQuantity
+	}
+
+	if quantity > limit {
+		return errQuantityTooLarge
+	}
+
+	if err := lockOpenCart(ctx, tx, cartID); err != nil {
+		return err
 	}
 
 	now := time.Now()
 
-	if exists {
-		li.Quantity += quantity
-		li.UpdatedAt = now
-		err = updateLineItem(ctx, tx, cartID, li)
-	} else {
+	li, err := lineItem(ctx, tx, cartID, productID, options)
+	old := li.Quantity
+
+	switch {
+	case err == errNotFound:
 		li = LineItem{
 			ProductID: productID,
 			Quantity:  quantity,
+			Options:   options,
+			Note:      note,
 			CreatedAt: now,
 			UpdatedAt: now,
 		}
 		err = createLineItem(ctx, tx, cartID, li)
+	case err != nil:
+		return err
+	case uint64(li.Quantity)+uint64(quantity) > uint64(limit):
+		return errQuantityTooLarge
+	default:
+		li.Quantity += quantity
+		if note != "" {
+			li.Note = note
+		}
+		li.UpdatedAt = now
+		err = updateLineItem(ctx, tx, cartID, li)
 	}
 
 	if err != nil {
-		if err := tx.Rollback(); err != nil {
-			log.Printf("Rollback failed: %s", err)
-		}
 		return err
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %s", err)
+	if err := checkRules(ctx, tx, cartID, productID, true); err != nil {
+		return err
 	}
 
-	return nil
+	h := HistoryEntry{CartID: cartID, Operation: EventProductAdded, ProductID: productID, OldQuantity: old, NewQuantity: li.Quantity, CreatedAt: now}
+	if err := writeHistory(ctx, tx, h); err != nil {
+		return err
+	}
+
+	return recordChange(ctx, tx, Event{Type: EventProductAdded, CartID: cartID, ProductID: productID, Options: options, Quantity: quantity, CreatedAt: now})
 }
 
-func (s *Storage) DeleteProduct(ctx context.Context, cartID, productID int64) error {
-	if _, err := s.db.ExecContext(ctx, sqlDeleteLineItem, cartID, productID); err != nil {
-		if err == sql.ErrNoRows {
-			return errNotFound
-		}
+// AddProduct with normalized Options to the Cart. Zero limit means there is no Product specific limit of quantity.
+func (s *Storage) AddProduct(ctx context.Context, cartID, productID int64, options Options, quantity uint32, note string, limit uint32) error {
+	return s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		return addLineItem(ctx, tx, cartID, productID, options, quantity, note, limit)
+	})
+}
 
+func deleteLineItem(ctx context.Context, tx *sql.Tx, cartID, productID int64, options Options) error {
+	if err := lockOpenCart(ctx, tx, cartID); err != nil {
 		return err
 	}
 
-	return nil
+	li, err := lineItem(ctx, tx, cartID, productID, options)
+	if err != nil && err != errNotFound {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, sqlDeleteLineItem, cartID, productID, options.key()); err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	h := HistoryEntry{CartID: cartID, Operation: EventProductDeleted, ProductID: productID, OldQuantity: li.Quantity, CreatedAt: now}
+	if err := writeHistory(ctx, tx, h); err != nil {
+		return err
+	}
+
+	return recordChange(ctx, tx, Event{Type: EventProductDeleted, CartID: cartID, ProductID: productID, Options: options, CreatedAt: now})
 }
 
-func (s *Storage) CartByID(ctx context.Context, id int64) (Cart, error) {
-	tx, err := s.db.BeginTx(ctx, readOnly)
-	if err != nil {
-		return Cart{}, fmt.Errorf("failed to start transaction: %s", err)
+// DeleteProduct with normalized Options from the Cart.
+func (s *Storage) DeleteProduct(ctx context.Context, cartID, productID int64, options Options) error {
+	return s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		return deleteLineItem(ctx, tx, cartID, productID, options)
+	})
+}
+
+func setLineItemQuantity(ctx context.Context, tx *sql.Tx, cartID, productID int64, options Options, quantity uint32) error {
+	if quantity == 0 {
+		return deleteLineItem(ctx, tx, cartID, productID, options)
 	}
 
-	cart := Cart{ID: id}
+	if quantity > maxQuantity {
+		return errQuantityTooLarge
+	}
 
-	row := tx.QueryRowContext(ctx, sqlCartByID, id)
-	if err := row.Scan(&cart.UserID, &cart.CreatedAt, &cart.UpdatedAt); err != nil {
-		if err == sql.ErrNoRows {
-			return Cart{}, errNotFound
-		}
+	if err := lockOpenCart(ctx, tx, cartID); err != nil {
+		return err
+	}
 
-		if err := tx.Rollback(); err != nil {
-			log.Printf("Rollback failed: %s", err)
+	now := time.Now()
+
+	li, err := lineItem(ctx, tx, cartID, productID, options)
+	old := li.Quantity
+
+	switch {
+	case err == errNotFound:
+		li = LineItem{
+			ProductID: productID,
+			Quantity:  quantity,
+			Options:   options,
+			CreatedAt: now,
+			UpdatedAt: now,
 		}
-		return Cart{}, err
+		err = createLineItem(ctx, tx, cartID, li)
+	case err != nil:
+		return err
+	default:
+		li.Quantity = quantity
+		li.UpdatedAt = now
+		err = updateLineItem(ctx, tx, cartID, li)
 	}
 
-	rows, err := tx.QueryContext(ctx, sqlLinesByCartID, id)
 	if err != nil {
-		if err := tx.Rollback(); err != nil {
-			log.Printf("Rollback failed: %s", err)
-		}
+		return err
+	}
 
-		if err == sql.ErrNoRows {
-			return Cart{}, errNotFound
-		}
+	if err := checkRules(ctx, tx, cartID, productID, true); err != nil {
+		return err
+	}
 
-		return Cart{}, err
+	h := HistoryEntry{CartID: cartID, Operation: EventProductQuantitySet, ProductID: productID, OldQuantity: old, NewQuantity: quantity, CreatedAt: now}
+	if err := writeHistory(ctx, tx, h); err != nil {
+		return err
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		li := LineItem{}
-		if err := rows.Scan(&li.ProductID, &li.Quantity); err != nil {
-			return Cart{}, fmt.Errorf("failed to scan row into LineItem sruct: %s", err)
-		}
-		cart.Items = append(cart.Items, li)
+	return recordChange(ctx, tx, Event{Type: EventProductQuantitySet, CartID: cartID, ProductID: productID, Options: options, Quantity: quantity, CreatedAt: now})
+}
+
+// SetProductQuantity of the Product with normalized Options in the Cart.
+func (s *Storage) SetProductQuantity(ctx context.Context, cartID, productID int64, options Options, quantity uint32) error {
+	return s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		return setLineItemQuantity(ctx, tx, cartID, productID, options, quantity)
+	})
+}
+
+func removeLineItemUnits(ctx context.Context, tx *sql.Tx, cartID, productID int64, options Options, quantity uint32) error {
+	if err := lockOpenCart(ctx, tx, cartID); err != nil {
+		return err
 	}
 
-	if err := rows.Err(); err != nil {
-		if err := tx.Rollback(); err != nil {
-			log.Printf("Rollback failed: %s", err)
+	li, err := lineItem(ctx, tx, cartID, productID, options)
+	if err != nil {
+		if err == errNotFound {
+			return errLineItemNotFound
 		}
+		return err
+	}
 
-		return Cart{}, fmt.Errorf("failed to iterate over DB rows: %s", err)
+	if quantity > li.Quantity {
+		return errNotEnoughQuantity
 	}
 
-	if err := tx.Commit(); err != nil {
-		return Cart{}, fmt.Errorf("failed to commit transaction: %s", err)
+	if quantity == li.Quantity {
+		return deleteLineItem(ctx, tx, cartID, productID, options)
 	}
 
-	return cart, nil
-}
+	now := time.Now()
 
-func (s *Storage) CreateCart(ctx context.Context, cart Cart) (Cart, error) {
-	err := s.db.QueryRowContext(ctx, sqlCreateCart, cart.UserID, cart.CreatedAt, cart.UpdatedAt).Scan(&cart.ID)
-	if err != nil {
-		return Cart{}, err
-	}
+	h := HistoryEntry{CartID: cartID, Operation: EventProductUnitsRemoved, ProductID: productID, OldQuantity: li.Quantity, CreatedAt: now}
 
-	return cart, nil
-}
+	li.Quantity -= quantity
+	li.UpdatedAt = now
 
-func deleteLineItems(ctx context.Context, tx *sql.Tx, cartID int64) error {
-	if _, err := tx.ExecContext(ctx, sqlDeleteLineItems, cartID); err != nil {
-		if err == sql.ErrNoRows {
-			return errNotFound
-		}
+	if err := updateLineItem(ctx, tx, cartID, li); err != nil {
+		return err
+	}
 
+	// Fewer units can not break Rules of the whole Cart.
+	if err := checkRules(ctx, tx, cartID, productID, false); err != nil {
 		return err
 	}
 
-	return nil
+	h.NewQuantity = li.Quantity
+	if err := writeHistory(ctx, tx, h); err != nil {
+		return err
+	}
+
+	return recordChange(ctx, tx, Event{Type: EventProductUnitsRemoved, CartID: cartID, ProductID: productID, Options: options, Quantity: quantity, CreatedAt: now})
 }
 
-func (s *Storage) DeleteCart(ctx context.Context, cartID int64) error {
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to start transaction: %s", err)
+// RemoveProductUnits of the Product with normalized Options from the Cart.
+func (s *Storage) RemoveProductUnits(ctx context.Context, cartID, productID int64, options Options, quantity uint32) error {
+	return s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		return removeLineItemUnits(ctx, tx, cartID, productID, options, quantity)
+	})
+}
+
+// moveLineItem of the Product with normalized Options between active and saved items of the Cart.
+// The LineItem keeps its quantity and timestamps, unless it is already in the destination list, then quantities are combined.
+// Resulting quantity of an active LineItem can not exceed the limit, zero limit means maxQuantity.
+func moveLineItem(ctx context.Context, tx *sql.Tx, cartID, productID int64, options Options, save bool, limit uint32) error {
+	if save || limit == 0 || limit > maxQuantity {
+		limit = maxQuantity
 	}
 
-	if err := deleteLineItems(ctx, tx, cartID); err != nil {
-		if err != errNotFound {
-			if err := tx.Rollback(); err != nil {
-				log.Printf("Rollback failed: %s", err)
-			}
-			return err
-		}
+	if err := lockOpenCart(ctx, tx, cartID); err != nil {
+		return err
 	}
 
-	if _, err := tx.ExecContext(ctx, sqlDeleteCart, cartID); err != nil {
-		if err != sql.ErrNoRows {
-			if err := tx.Rollback(); err != nil {
-				log.Printf("Rollback failed: %s", err)
-			}
-			return err
+	var (
+		key                = options.key()
+		quantity, existing uint32
+	)
+
+	if err := tx.QueryRowContext(ctx, sqlListedItem, cartID, productID, key, !save).Scan(&quantity); err != nil {
+		if err == sql.ErrNoRows {
+			return errLineItemNotFound
 		}
+		return err
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %s", err)
-	}
+	now := time.Now()
 
-	return nil
-}
+	err := tx.QueryRowContext(ctx, sqlListedItem, cartID, productID, key, save).Scan(&existing)
+	switch {
+	case err == sql.ErrNoRows:
+		if quantity > limit {
+			return errQuantityTooLarge
+		}
+		_, err = tx.ExecContext(ctx, sqlMoveLineItem, cartID, productID, key, save)
+	case err != nil:
+		return err
+	case uint64(existing)+uint64(quantity) > uint64(limit):
+		return errQuantityTooLarge
+	default:
+		if _, err = tx.ExecContext(ctx, sqlUpdateListed, cartID, productID, key, save, existing+quantity, now); err == nil {
+			_, err = tx.ExecContext(ctx, sqlDeleteListed, cartID, productID, key, !save)
+		}
+	}
 
-func (s *Storage) DeleteLineItems(ctx context.Context, cartID int64) error {
-	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to start transaction: %s", err)
+		return err
 	}
 
-	if err := deleteLineItems(ctx, tx, cartID); err != nil {
-		if err != errNotFound {
-			if err := tx.Rollback(); err != nil {
-				log.Printf("Rollback failed: %s", err)
-			}
+	if !save {
+		if err := checkRules(ctx, tx, cartID, productID, true); err != nil {
 			return err
 		}
 	}
 
-	if _, err := tx.ExecContext(ctx, sqlUpdateCartTS, cartID, time.Now()); err != nil {
-		if err := tx.Rollback(); err != nil {
-			log.Printf("Rollback failed: %s", err)
-		}
-
-		if err == sql.ErrNoRows {
-			return errNotFound
-		}
+	// History tracks quantity of the active LineItem.
+	h := HistoryEntry{CartID: cartID, Operation: EventProductMovedToCart, ProductID: productID, OldQuantity: existing, NewQuantity: existing + quantity, CreatedAt: now}
+	e := Event{Type: EventProductMovedToCart, CartID: cartID, ProductID: productID, Options: options, Quantity: quantity, CreatedAt: now}
+	if save {
+		h = HistoryEntry{CartID: cartID, Operation: EventProductSavedForLater, ProductID: productID, OldQuantity: quantity, CreatedAt: now}
+		e.Type = EventProductSavedForLater
+	}
 
+	if err := writeHistory(ctx, tx, h); err != nil {
 		return err
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %s", err)
-	}
+	return recordChange(ctx, tx, e)
+}
 
-	return nil
+// SaveForLater moves the Product with normalized Options from active items of the Cart to the saved ones.
+func (s *Storage) SaveForLater(ctx context.Context, cartID, productID int64, options Options) error {
+	return s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		return moveLineItem(ctx, tx, cartID, productID, options, true, 0)
+	})
+}
+
+// MoveToCart moves the Product with normalized Options from saved items of the Cart to the active ones.
+// Zero limit means there is no Product specific limit of quantity.
+func (s *Storage) MoveToCart(ctx context.Context, cartID, productID int64, options Options, limit uint32) error {
+	return s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		return moveLineItem(ctx, tx, cartID, productID, options, false, limit)
+	})
+}
+
+func lineItems(ctx context.Context, tx *sql.Tx, cartID int64) ([]LineItem, error) {
+	rows, err := tx.QueryContext(ctx, sqlLinesByCartID, cartID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []LineItem
+
+	for rows.Next() {
+		var (
+			li            LineItem
+			options       []byte
+			reservedUntil pq.NullTime
+		)
+		if err := rows.Scan(&li.ProductID, &li.Quantity, &options, &li.Note, &li.ReservationID, &reservedUntil); err != nil {
+			return nil, fmt.Errorf("failed to scan row into LineItem sruct: %s", err)
+		}
+		if li.Options, err = decodeOptions(options); err != nil {
+			return nil, err
+		}
+		li.ReservedUntil = reservedUntil.Time
+		items = append(items, li)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over DB rows: %s", err)
+	}
+
+	return items, nil
+}
+
+// savedItems returns LineItems saved for later in order of saving.
+func savedItems(ctx context.Context, tx *sql.Tx, cartID int64) ([]LineItem, error) {
+	rows, err := tx.QueryContext(ctx, sqlSavedByCartID, cartID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []LineItem
+
+	for rows.Next() {
+		var (
+			li      LineItem
+			options []byte
+		)
+		if err := rows.Scan(&li.ProductID, &li.Quantity, &options, &li.Note, &li.CreatedAt, &li.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row into LineItem struct: %s", err)
+		}
+		if li.Options, err = decodeOptions(options); err != nil {
+			return nil, err
+		}
+		items = append(items, li)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over DB rows: %s", err)
+	}
+
+	return items, nil
+}
+
+// coupons returns codes of coupons applied to the Cart in order of application.
+func coupons(ctx context.Context, tx *sql.Tx, cartID int64) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, sqlCouponsByCartID, cartID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var codes []string
+
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, fmt.Errorf("failed to scan row into coupon code: %s", err)
+		}
+		codes = append(codes, code)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over DB rows: %s", err)
+	}
+
+	return codes, nil
+}
+
+func loadCart(ctx context.Context, tx *sql.Tx, id int64) (Cart, error) {
+	cart := Cart{ID: id}
+
+	row := tx.QueryRowContext(ctx, sqlCartByID, id)
+	if err := row.Scan(&cart.UserID, &cart.Status, &cart.Version, &cart.CreatedAt, &cart.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return Cart{}, errNotFound
+		}
+		return Cart{}, err
+	}
+
+	items, err := lineItems(ctx, tx, id)
+	if err != nil {
+		return Cart{}, err
+	}
+	cart.Items = items
+
+	saved, err := savedItems(ctx, tx, id)
+	if err != nil {
+		return Cart{}, err
+	}
+	cart.SavedItems = saved
+
+	codes, err := coupons(ctx, tx, id)
+	if err != nil {
+		return Cart{}, err
+	}
+	cart.Coupons = codes
+
+	return cart, nil
+}
+
+// MergeCarts copies LineItems of the source Cart into the target Cart and soft-deletes the source Cart.
+// The source Cart keeps its content, so a mistaken merge can be undone by restoring it.
+func (s *Storage) MergeCarts(ctx context.Context, sourceID, targetID int64, strategy MergeStrategy) (Cart, error) {
+	var cart Cart
+
+	err := s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		// Carts are locked in the same order by all merges to avoid deadlocks.
+		// Version expected by the caller belongs to the target Cart.
+		ids := []int64{sourceID, targetID}
+		if sourceID > targetID {
+			ids[0], ids[1] = targetID, sourceID
+		}
+
+		for _, id := range ids {
+			var expected int64
+			if id == targetID {
+				expected = expectedVersion(ctx)
+			}
+
+			status, err := lockCart(ctx, tx, id, expected)
+			if err != nil {
+				return err
+			}
+			if status != StatusOpen {
+				return errCartNotOpen
+			}
+		}
+
+		items, err := lineItems(ctx, tx, sourceID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		detail := fmt.Sprintf("merged from cart: %d", sourceID)
+
+		for _, src := range items {
+			li, err := lineItem(ctx, tx, targetID, src.ProductID, src.Options)
+			old := li.Quantity
+
+			switch {
+			case err == errNotFound:
+				li = LineItem{
+					ProductID: src.ProductID,
+					Quantity:  src.Quantity,
+					Options:   src.Options,
+					Note:      src.Note,
+					CreatedAt: now,
+					UpdatedAt: now,
+				}
+				err = createLineItem(ctx, tx, targetID, li)
+			case err != nil:
+				return err
+			default:
+				if li.Quantity, err = mergeQuantity(strategy, li.Quantity, src.Quantity); err != nil {
+					return err
+				}
+				li.UpdatedAt = now
+				err = updateLineItem(ctx, tx, targetID, li)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			h := HistoryEntry{CartID: targetID, Operation: EventCartMerged, ProductID: src.ProductID, OldQuantity: old, NewQuantity: li.Quantity, Detail: detail, CreatedAt: now}
+			if err := writeHistory(ctx, tx, h); err != nil {
+				return err
+			}
+		}
+
+		if err := checkRules(ctx, tx, targetID, 0, true); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, sqlMergeSaved, sourceID, targetID, now); err != nil {
+			return err
+		}
+
+		// Coupons and members are not copied, they may not be valid for the target Cart User.
+		// The janitor purges the source Cart with its content after the retention period.
+		if _, err := tx.ExecContext(ctx, sqlSoftDeleteCart, sourceID, now); err != nil {
+			return err
+		}
+		deleted := HistoryEntry{CartID: sourceID, Operation: EventCartDeleted, Detail: fmt.Sprintf("merged into cart: %d", targetID), CreatedAt: now}
+		if err := writeHistory(ctx, tx, deleted); err != nil {
+			return err
+		}
+		if err := writeEvent(ctx, tx, Event{Type: EventCartDeleted, CartID: sourceID, CreatedAt: now}); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, tx, Event{Type: EventCartMerged, CartID: targetID, SourceCartID: sourceID, CreatedAt: now}); err != nil {
+			return err
+		}
+
+		cart, err = loadCart(ctx, tx, targetID)
+		return err
+	})
+	if err != nil {
+		return Cart{}, err
+	}
+
+	return cart, nil
+}
+
+func (s *Storage) CartByID(ctx context.Context, id int64) (Cart, error) {
+	var cart Cart
+
+	err := s.withTx(ctx, readOnly, func(tx *sql.Tx) error {
+		var err error
+		cart, err = loadCart(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		return Cart{}, err
+	}
+
+	return cart, nil
+}
+
+// CartsByUser returns up to limit Carts of the User, newest first, with IDs lower than beforeID.
+// Zero beforeID starts from the newest Cart.
+func (s *Storage) CartsByUser(ctx context.Context, userID, beforeID int64, limit int) ([]Cart, error) {
+	var carts []Cart
+
+	err := s.withTx(ctx, readOnly, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, sqlCartsByUser, userID, beforeID, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			cart := Cart{UserID: userID}
+			if err := rows.Scan(&cart.ID, &cart.Status, &cart.Version, &cart.CreatedAt, &cart.UpdatedAt); err != nil {
+				return fmt.Errorf("failed to scan row into Cart sruct: %s", err)
+			}
+			carts = append(carts, cart)
+		}
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to iterate over DB rows: %s", err)
+		}
+
+		for i := range carts {
+			if carts[i].Items, err = lineItems(ctx, tx, carts[i].ID); err != nil {
+				return err
+			}
+			if carts[i].SavedItems, err = savedItems(ctx, tx, carts[i].ID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return carts, nil
+}
+
+// ActiveCart returns the most recently updated open Cart of the User.
+func (s *Storage) ActiveCart(ctx context.Context, userID int64) (Cart, error) {
+	var cart Cart
+
+	err := s.withTx(ctx, readOnly, func(tx *sql.Tx) error {
+		var id int64
+		if err := tx.QueryRowContext(ctx, sqlActiveCartID, userID).Scan(&id); err != nil {
+			if err == sql.ErrNoRows {
+				return errNotFound
+			}
+			return err
+		}
+
+		var err error
+		cart, err = loadCart(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		return Cart{}, err
+	}
+
+	return cart, nil
+}
+
+func insertCart(ctx context.Context, tx *sql.Tx, cart *Cart) error {
+	if err := lockUser(ctx, tx, cart.UserID); err != nil {
+		return err
+	}
+
+	err := tx.QueryRowContext(ctx, sqlCreateCart, cart.UserID, cart.Status, cart.CreatedAt, cart.UpdatedAt).Scan(&cart.ID, &cart.Version)
+	if err != nil {
+		return err
+	}
+
+	h := HistoryEntry{CartID: cart.ID, Operation: EventCartCreated, Detail: fmt.Sprintf("user: %d", cart.UserID), CreatedAt: cart.CreatedAt}
+	if err := writeHistory(ctx, tx, h); err != nil {
+		return err
+	}
+
+	return writeEvent(ctx, tx, Event{Type: EventCartCreated, CartID: cart.ID, UserID: cart.UserID, CreatedAt: cart.CreatedAt})
+}
+
+func (s *Storage) CreateCart(ctx context.Context, cart Cart) (Cart, error) {
+	err := s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		return insertCart(ctx, tx, &cart)
+	})
+	if err != nil {
+		return Cart{}, err
+	}
+
+	return cart, nil
+}
+
+// CloneCart creates the Cart with its Items copied from the source Cart. The source Cart must still have the Version
+// the Items were taken from, zero sourceID means the Items do not come from a Cart, e.g. they come from a Snapshot.
+func (s *Storage) CloneCart(ctx context.Context, sourceID, sourceVersion int64, cart Cart) (Cart, error) {
+	err := s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		if sourceID != 0 {
+			if _, err := lockCart(ctx, tx, sourceID, sourceVersion); err != nil {
+				return err
+			}
+		}
+
+		if err := insertCart(ctx, tx, &cart); err != nil {
+			return err
+		}
+
+		detail := fmt.Sprintf("cloned from cart: %d", sourceID)
+
+		for _, li := range cart.Items {
+			if err := createLineItem(ctx, tx, cart.ID, li); err != nil {
+				return err
+			}
+
+			h := HistoryEntry{CartID: cart.ID, Operation: EventProductAdded, ProductID: li.ProductID, NewQuantity: li.Quantity, Detail: detail, CreatedAt: li.CreatedAt}
+			if err := writeHistory(ctx, tx, h); err != nil {
+				return err
+			}
+
+			e := Event{Type: EventProductAdded, CartID: cart.ID, ProductID: li.ProductID, Options: li.Options, Quantity: li.Quantity, CreatedAt: li.CreatedAt}
+			if err := writeEvent(ctx, tx, e); err != nil {
+				return err
+			}
+		}
+
+		return checkRules(ctx, tx, cart.ID, 0, true)
+	})
+	if err != nil {
+		return Cart{}, err
+	}
+
+	return cart, nil
+}
+
+// GetOrCreateCart returns the active Cart of the User or creates the provided one when User has no open Carts.
+// Calls for the same User are serialized with a transaction level advisory lock keyed by User ID.
+func (s *Storage) GetOrCreateCart(ctx context.Context, cart Cart) (Cart, error) {
+	err := s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		if err := lockUser(ctx, tx, cart.UserID); err != nil {
+			return err
+		}
+
+		var id int64
+
+		err := tx.QueryRowContext(ctx, sqlActiveCartID, cart.UserID).Scan(&id)
+		switch {
+		case err == sql.ErrNoRows:
+			return insertCart(ctx, tx, &cart)
+		case err != nil:
+			return err
+		}
+
+		cart, err = loadCart(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		return Cart{}, err
+	}
+
+	return cart, nil
+}
+
+// DeleteCart soft-deletes the Cart. It is hidden from reads and can not be modified, but its content is kept
+// until the Cart is restored or purged.
+func (s *Storage) DeleteCart(ctx context.Context, cartID int64) error {
+	return s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		now := time.Now()
+
+		res, err := tx.ExecContext(ctx, sqlSoftDeleteCart, cartID, now)
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return errNotFound
+		}
+
+		if err := writeHistory(ctx, tx, HistoryEntry{CartID: cartID, Operation: EventCartDeleted, CreatedAt: now}); err != nil {
+			return err
+		}
+
+		return writeEvent(ctx, tx, Event{Type: EventCartDeleted, CartID: cartID, CreatedAt: now})
+	})
+}
+
+// DeleteExpiredCarts deletes up to limit Carts last changed before the time for their kind of User, or soft-deleted
+// before deletedBefore, together with their LineItems, coupons and members. Zero time expires no Carts.
+// Returns numbers of deleted Carts and LineItems.
+func (s *Storage) DeleteExpiredCarts(ctx context.Context, anonymousBefore, authenticatedBefore, deletedBefore time.Time, limit int) (int, int, error) {
+	var (
+		ids   []int64
+		items int64
+	)
+
+	err := s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, sqlExpiredCarts, anonymousBefore, authenticatedBefore, deletedBefore, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		// Deletion of soft-deleted Carts is already recorded.
+		var expired []int64
+
+		for rows.Next() {
+			var (
+				id      int64
+				deleted bool
+			)
+			if err := rows.Scan(&id, &deleted); err != nil {
+				return fmt.Errorf("failed to scan row into Cart ID: %s", err)
+			}
+			ids = append(ids, id)
+			if !deleted {
+				expired = append(expired, id)
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to iterate over DB rows: %s", err)
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		res, err := tx.ExecContext(ctx, sqlDeleteCartsLineItems, pq.Array(ids))
+		if err != nil {
+			return err
+		}
+		if items, err = res.RowsAffected(); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, sqlDeleteCartsCoupons, pq.Array(ids)); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, sqlDeleteCartsMembers, pq.Array(ids)); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, sqlDeleteCarts, pq.Array(ids)); err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		for _, id := range expired {
+			if err := writeHistory(ctx, tx, HistoryEntry{CartID: id, Operation: EventCartDeleted, Detail: "expired", CreatedAt: now}); err != nil {
+				return err
+			}
+			if err := writeEvent(ctx, tx, Event{Type: EventCartDeleted, CartID: id, CreatedAt: now}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return len(ids), int(items), nil
+}
+
+// MarkAbandonedCarts marks up to limit non-empty open Carts not changed since idleBefore as abandoned and writes
+// EventCartAbandoned with their content for each of them. A Cart is marked again only after it is changed.
+// Returns number of marked Carts.
+func (s *Storage) MarkAbandonedCarts(ctx context.Context, idleBefore, now time.Time, limit int) (int, error) {
+	var events []Event
+
+	err := s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, sqlIdleCarts, idleBefore, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		var ids []int64
+
+		for rows.Next() {
+			e := Event{Type: EventCartAbandoned, CreatedAt: now}
+			if err := rows.Scan(&e.CartID, &e.UserID); err != nil {
+				return fmt.Errorf("failed to scan row into Cart: %s", err)
+			}
+
+			ids = append(ids, e.CartID)
+			events = append(events, e)
+		}
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to iterate over DB rows: %s", err)
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if _, err := tx.ExecContext(ctx, sqlMarkAbandoned, pq.Array(ids), now); err != nil {
+			return err
+		}
+
+		for i := range events {
+			items, err := lineItems(ctx, tx, events[i].CartID)
+			if err != nil {
+				return err
+			}
+
+			for _, li := range items {
+				events[i].Items = append(events[i].Items, EventItem{ProductID: li.ProductID, Options: li.Options, Quantity: li.Quantity})
+			}
+
+			if err := writeEvent(ctx, tx, events[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(events), nil
+}
+
+// RestoreCart soft-deleted after deletedAfter. Carts deleted earlier are past retention and can not be restored.
+func (s *Storage) RestoreCart(ctx context.Context, cartID int64, deletedAfter time.Time) error {
+	return s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		var deletedAt pq.NullTime
+
+		if err := tx.QueryRowContext(ctx, sqlLockDeleted, cartID).Scan(&deletedAt); err != nil {
+			if err == sql.ErrNoRows {
+				return errNotFound
+			}
+			return err
+		}
+
+		switch {
+		case !deletedAt.Valid:
+			return errCartNotDeleted
+		case deletedAt.Time.Before(deletedAfter):
+			return errRetentionExpired
+		}
+
+		// Restored open Cart may be the active one of its User.
+		if err := lockCartUser(ctx, tx, cartID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		if _, err := tx.ExecContext(ctx, sqlRestoreCart, cartID, now); err != nil {
+			return err
+		}
+
+		if err := writeHistory(ctx, tx, HistoryEntry{CartID: cartID, Operation: EventCartRestored, CreatedAt: now}); err != nil {
+			return err
+		}
+
+		return writeEvent(ctx, tx, Event{Type: EventCartRestored, CartID: cartID, CreatedAt: now})
+	})
+}
+
+func emptyCart(ctx context.Context, tx *sql.Tx, cartID int64) error {
+	if err := lockOpenCart(ctx, tx, cartID); err != nil {
+		return err
+	}
+
+	items, err := lineItems(ctx, tx, cartID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, sqlEmptyCart, cartID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, li := range items {
+		h := HistoryEntry{CartID: cartID, Operation: EventCartEmptied, ProductID: li.ProductID, OldQuantity: li.Quantity, CreatedAt: now}
+		if err := writeHistory(ctx, tx, h); err != nil {
+			return err
+		}
+	}
+
+	return recordChange(ctx, tx, Event{Type: EventCartEmptied, CartID: cartID, CreatedAt: now})
+}
+
+func (s *Storage) DeleteLineItems(ctx context.Context, cartID int64) error {
+	return s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		return emptyCart(ctx, tx, cartID)
+	})
+}
+
+// applyOperation to the Cart and return the resulting quantity of the LineItem.
+func applyOperation(ctx context.Context, tx *sql.Tx, cartID int64, op Operation) (uint32, error) {
+	var err error
+
+	switch op.Type {
+	case OperationAdd:
+		err = addLineItem(ctx, tx, cartID, op.ProductID, op.Options, op.Quantity, op.Note, op.limit)
+	case OperationSet:
+		err = setLineItemQuantity(ctx, tx, cartID, op.ProductID, op.Options, op.Quantity)
+	case OperationRemove:
+		if op.Quantity == 0 {
+			err = deleteLineItem(ctx, tx, cartID, op.ProductID, op.Options)
+		} else {
+			err = removeLineItemUnits(ctx, tx, cartID, op.ProductID, op.Options, op.Quantity)
+		}
+	case OperationEmpty:
+		return 0, emptyCart(ctx, tx, cartID)
+	default:
+		return 0, errUnknownOperation
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	li, err := lineItem(ctx, tx, cartID, op.ProductID, op.Options)
+	if err != nil && err != errNotFound {
+		return 0, err
+	}
+
+	return li.Quantity, nil
+}
+
+// ApplyOperations to the Cart in one transaction and return resulting quantities of their Products.
+// Index of the failed Operation is returned with the error, it is -1 when the error is not caused by an Operation.
+func (s *Storage) ApplyOperations(ctx context.Context, cartID int64, ops []Operation) ([]uint32, int, error) {
+	var (
+		quantities = make([]uint32, 0, len(ops))
+		failed     = -1
+	)
+
+	err := s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		if err := lockOpenCart(ctx, tx, cartID); err != nil {
+			return err
+		}
+
+		// Every Operation changes the Cart Version, the expected one is checked only before the first of them.
+		// Rules are checked once for the result of all Operations, e.g. quantity can go through a value that is not allowed.
+		opCtx := withRules(WithExpectedVersion(ctx, 0), nil)
+
+		for i, op := range ops {
+			q, err := applyOperation(opCtx, tx, cartID, op)
+			if err != nil {
+				failed = i
+				return err
+			}
+			quantities = append(quantities, q)
+		}
+
+		return checkRules(ctx, tx, cartID, 0, true)
+	})
+	if err != nil {
+		return nil, failed, err
+	}
+
+	return quantities, -1, nil
+}
+
+// UpdateStatus of the Cart if it is still in the from Status and returns the updated Cart.
+func (s *Storage) UpdateStatus(ctx context.Context, cartID int64, from, to Status, ts time.Time) (Cart, error) {
+	var cart Cart
+
+	err := s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		status, err := lockCart(ctx, tx, cartID, expectedVersion(ctx))
+		if err != nil {
+			return err
+		}
+
+		if status != from {
+			return errInvalidTransition
+		}
+
+		if to == StatusOpen {
+			if err := lockCartUser(ctx, tx, cartID); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, sqlUpdateStatus, cartID, to, ts); err != nil {
+			return err
+		}
+
+		if err := writeHistory(ctx, tx, HistoryEntry{CartID: cartID, Operation: EventCartStatusChanged, Detail: string(to), CreatedAt: ts}); err != nil {
+			return err
+		}
+		if err := writeEvent(ctx, tx, Event{Type: EventCartStatusChanged, CartID: cartID, Status: to, CreatedAt: ts}); err != nil {
+			return err
+		}
+
+		cart, err = loadCart(ctx, tx, cartID)
+		return err
+	})
+	if err != nil {
+		return Cart{}, err
+	}
+
+	return cart, nil
+}
+
+// AddCoupon applies the coupon to the open Cart. Coupon that is already applied is kept as is.
+func (s *Storage) AddCoupon(ctx context.Context, cartID int64, code string, ts time.Time) error {
+	return s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		if err := lockOpenCart(ctx, tx, cartID); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, sqlCreateCoupon, cartID, code, ts); err != nil {
+			return err
+		}
+
+		if err := writeHistory(ctx, tx, HistoryEntry{CartID: cartID, Operation: EventCouponApplied, Detail: code, CreatedAt: ts}); err != nil {
+			return err
+		}
+
+		return recordChange(ctx, tx, Event{Type: EventCouponApplied, CartID: cartID, Coupon: code, CreatedAt: ts})
+	})
+}
+
+// DeleteCoupon removes the coupon from the open Cart.
+func (s *Storage) DeleteCoupon(ctx context.Context, cartID int64, code string) error {
+	return s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		if err := lockOpenCart(ctx, tx, cartID); err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx, sqlDeleteCoupon, cartID, code)
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return errCouponNotApplied
+		}
+
+		now := time.Now()
+
+		if err := writeHistory(ctx, tx, HistoryEntry{CartID: cartID, Operation: EventCouponRemoved, Detail: code, CreatedAt: now}); err != nil {
+			return err
+		}
+
+		return recordChange(ctx, tx, Event{Type: EventCouponRemoved, CartID: cartID, Coupon: code, CreatedAt: now})
+	})
+}
+
+// ReserveIdempotencyKey for the method. If the key is already used by the same caller and not expired,
+// response stored for it is returned. errRequestInProgress is returned when there is no stored response yet.
+func (s *Storage) ReserveIdempotencyKey(ctx context.Context, key, method string, now time.Time, ttl time.Duration) ([]byte, bool, error) {
+	scope := idempotencyScope(ctx)
+
+	res, err := s.db.ExecContext(ctx, sqlReserveIdempotencyKey, key, method, scope, now, now.Add(ttl))
+	if err != nil {
+		return nil, false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if n > 0 {
+		return nil, true, nil
+	}
+
+	var (
+		completed bool
+		resp      []byte
+	)
+
+	if err := s.db.QueryRowContext(ctx, sqlIdempotentResponse, key, method, scope).Scan(&completed, &resp); err != nil {
+		if err == sql.ErrNoRows {
+			// Key was released in the meantime.
+			return nil, false, errRequestInProgress
+		}
+		return nil, false, err
+	}
+
+	if !completed {
+		return nil, false, errRequestInProgress
+	}
+
+	return resp, false, nil
+}
+
+// SaveIdempotentResponse stores the response of the request made with reserved key and keeps it until expiresAt.
+func (s *Storage) SaveIdempotentResponse(ctx context.Context, key, method string, resp []byte, expiresAt time.Time) error {
+	if resp == nil {
+		resp = []byte{}
+	}
+
+	_, err := s.db.ExecContext(ctx, sqlSaveIdempotentResponse, key, method, idempotencyScope(ctx), resp, expiresAt)
+	return err
+}
+
+// ReleaseIdempotencyKey so the request with the key can be made again.
+func (s *Storage) ReleaseIdempotencyKey(ctx context.Context, key, method string) error {
+	_, err := s.db.ExecContext(ctx, sqlDeleteIdempotencyKey, key, method, idempotencyScope(ctx))
+	return err
+}
+
+// DeleteExpiredIdempotencyKeys deletes up to limit idempotency keys expired before now with their responses.
+// Returns number of deleted keys.
+func (s *Storage) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time, limit int) (int, error) {
+	res, err := s.db.ExecContext(ctx, sqlDeleteExpiredIdempotency, now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
+
+// Reservations returns stock reservations of the Cart LineItems by Product IDs.
+func (s *Storage) Reservations(ctx context.Context, cartID int64) (map[int64]Reservation, error) {
+	rows, err := s.db.QueryContext(ctx, sqlReservations, cartID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reservations := make(map[int64]Reservation)
+
+	for rows.Next() {
+		var (
+			productID int64
+			r         Reservation
+		)
+		if err := rows.Scan(&productID, &r.ID, &r.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row into Reservation struct: %s", err)
+		}
+		reservations[productID] = r
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over DB rows: %s", err)
+	}
+
+	return reservations, nil
+}
+
+// SaveReservations of the Cart LineItems by Product IDs. Zero Reservation removes reservation from the LineItem.
+// Cart Version is not changed, reservations are not a part of the Cart content.
+func (s *Storage) SaveReservations(ctx context.Context, cartID int64, reservations map[int64]Reservation) error {
+	return s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		for productID, r := range reservations {
+			var (
+				id        = sql.NullString{String: r.ID, Valid: r.ID != ""}
+				expiresAt = pq.NullTime{Time: r.ExpiresAt, Valid: r.ID != ""}
+			)
+
+			if _, err := tx.ExecContext(ctx, sqlUpdateReservation, cartID, productID, id, expiresAt); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ExpiredReservations returns up to limit LineItem reservations that expired before the provided time, oldest first.
+func (s *Storage) ExpiredReservations(ctx context.Context, before time.Time, limit int) ([]reservedLineItem, error) {
+	rows, err := s.db.QueryContext(ctx, sqlExpiredReservations, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var expired []reservedLineItem
+
+	for rows.Next() {
+		var r reservedLineItem
+		if err := rows.Scan(&r.CartID, &r.ProductID, &r.ID, &r.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row into Reservation struct: %s", err)
+		}
+		expired = append(expired, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over DB rows: %s", err)
+	}
+
+	return expired, nil
+}
+
+// DeletePublishedEvents deletes up to limit Events published before the time from the outbox.
+// Returns number of deleted Events.
+func (s *Storage) DeletePublishedEvents(ctx context.Context, publishedBefore time.Time, limit int) (int, error) {
+	res, err := s.db.ExecContext(ctx, sqlDeleteEvents, publishedBefore, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
+
+// PublishEvents passes up to limit pending Events from the outbox to publish and marks them published at ts.
+// Events locked by another publisher are skipped. It returns the number of published Events.
+func (s *Storage) PublishEvents(ctx context.Context, limit int, ts time.Time, publish func([]Event) error) (int, error) {
+	var events []Event
+
+	err := s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, sqlPendingEvents, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		var ids []int64
+
+		for rows.Next() {
+			var (
+				id      int64
+				payload []byte
+				e       Event
+			)
+			if err := rows.Scan(&id, &payload); err != nil {
+				return fmt.Errorf("failed to scan row into Event: %s", err)
+			}
+			if err := json.Unmarshal(payload, &e); err != nil {
+				return fmt.Errorf("failed to unmarshal the Event: %d, error: %s", id, err)
+			}
+			e.ID = id
+
+			ids = append(ids, id)
+			events = append(events, e)
+		}
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to iterate over DB rows: %s", err)
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		if err := publish(events); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, sqlPublishEvents, pq.Array(ids), ts)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(events), nil
+}
+
+// History returns up to limit changes of the Cart, newest first, with IDs lower than beforeID.
+// Zero beforeID starts from the newest change.
+func (s *Storage) History(ctx context.Context, cartID, beforeID int64, limit int) ([]HistoryEntry, error) {
+	rows, err := s.db.QueryContext(ctx, sqlHistory, cartID, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []HistoryEntry
+
+	for rows.Next() {
+		h := HistoryEntry{CartID: cartID}
+		err := rows.Scan(&h.ID, &h.Actor, &h.Operation, &h.ProductID, &h.OldQuantity, &h.NewQuantity, &h.Detail, &h.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row into HistoryEntry struct: %s", err)
+		}
+		entries = append(entries, h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over DB rows: %s", err)
+	}
+
+	return entries, nil
+}
+
+// CreateSnapshot of the Cart and return it with the ID. Snapshot is saved only if the Cart still has the Version of the copy.
+func (s *Storage) CreateSnapshot(ctx context.Context, snapshot Snapshot) (Snapshot, error) {
+	content, err := json.Marshal(snapshot.Cart)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to marshal the Cart: %s", err)
+	}
+
+	err = s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		cart := snapshot.Cart
+		if _, err := lockCart(ctx, tx, cart.ID, cart.Version); err != nil {
+			return err
+		}
+
+		row := tx.QueryRowContext(ctx, sqlCreateSnapshot, cart.ID, cart.Version, content, snapshot.Hash, snapshot.CreatedAt)
+		return row.Scan(&snapshot.ID)
+	})
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	return snapshot, nil
+}
+
+func (s *Storage) Snapshot(ctx context.Context, id int64) (Snapshot, error) {
+	var (
+		snapshot = Snapshot{ID: id}
+		content  []byte
+	)
+
+	row := s.db.QueryRowContext(ctx, sqlSnapshotByID, id)
+	if err := row.Scan(&content, &snapshot.Hash, &snapshot.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return Snapshot{}, errSnapshotNotFound
+		}
+		return Snapshot{}, err
+	}
+
+	if err := json.Unmarshal(content, &snapshot.Cart); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to unmarshal the Cart: %s", err)
+	}
+
+	return snapshot, nil
+}
+
+// MemberRole of the User in the Cart, it is empty when the User is not a member. Deleted Carts are included.
+func (s *Storage) MemberRole(ctx context.Context, cartID, userID int64) (Role, error) {
+	var role Role
+
+	if err := s.db.QueryRowContext(ctx, sqlMemberRole, cartID, userID).Scan(&role); err != nil {
+		if err == sql.ErrNoRows {
+			return "", errNotFound
+		}
+		return "", err
+	}
+
+	return role, nil
+}
+
+// Members of the Cart, the owner is the first of them.
+func (s *Storage) Members(ctx context.Context, cartID int64) ([]Member, error) {
+	var members []Member
+
+	err := s.withTx(ctx, readOnly, func(tx *sql.Tx) error {
+		owner := Member{Role: RoleOwner}
+
+		if err := tx.QueryRowContext(ctx, sqlCartOwner, cartID).Scan(&owner.UserID, &owner.CreatedAt); err != nil {
+			if err == sql.ErrNoRows {
+				return errNotFound
+			}
+			return err
+		}
+
+		members = append(members, owner)
+
+		rows, err := tx.QueryContext(ctx, sqlMembers, cartID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var m Member
+			if err := rows.Scan(&m.UserID, &m.Role, &m.CreatedAt); err != nil {
+				return fmt.Errorf("failed to scan row into Member: %s", err)
+			}
+			members = append(members, m)
+		}
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to iterate over DB rows: %s", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// lockCartOwner locks the Cart for a membership change and returns its owner.
+func lockCartOwner(ctx context.Context, tx *sql.Tx, cartID int64) (int64, error) {
+	var owner int64
+
+	if err := tx.QueryRowContext(ctx, sqlLockOwner, cartID).Scan(&owner); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, errNotFound
+		}
+		return 0, err
+	}
+
+	return owner, nil
+}
+
+// AddMember to the Cart or change Role of an existing one.
+func (s *Storage) AddMember(ctx context.Context, cartID int64, m Member) error {
+	return s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		owner, err := lockCartOwner(ctx, tx, cartID)
+		if err != nil {
+			return err
+		}
+		if owner == m.UserID {
+			return errOwnerMembership
+		}
+
+		if _, err := tx.ExecContext(ctx, sqlAddMember, cartID, m.UserID, m.Role, m.CreatedAt); err != nil {
+			return err
+		}
+
+		h := HistoryEntry{CartID: cartID, Operation: EventMemberAdded, Detail: fmt.Sprintf("user: %d, role: %s", m.UserID, m.Role), CreatedAt: m.CreatedAt}
+		if err := writeHistory(ctx, tx, h); err != nil {
+			return err
+		}
+
+		return writeEvent(ctx, tx, Event{Type: EventMemberAdded, CartID: cartID, UserID: m.UserID, Role: m.Role, CreatedAt: m.CreatedAt})
+	})
+}
+
+// RemoveMember from the Cart. The owner can not be removed.
+func (s *Storage) RemoveMember(ctx context.Context, cartID, userID int64) error {
+	return s.withTx(ctx, nil, func(tx *sql.Tx) error {
+		owner, err := lockCartOwner(ctx, tx, cartID)
+		if err != nil {
+			return err
+		}
+		if owner == userID {
+			return errOwnerMembership
+		}
+
+		res, err := tx.ExecContext(ctx, sqlRemoveMember, cartID, userID)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return errMemberNotFound
+		}
+
+		now := time.Now()
+
+		h := HistoryEntry{CartID: cartID, Operation: EventMemberRemoved, Detail: fmt.Sprintf("user: %d", userID), CreatedAt: now}
+		if err := writeHistory(ctx, tx, h); err != nil {
+			return err
+		}
+
+		return writeEvent(ctx, tx, Event{Type: EventMemberRemoved, CartID: cartID, UserID: userID, CreatedAt: now})
+	})
 }
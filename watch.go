@@ -0,0 +1,142 @@
+package cart
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// changesChannel is Postgres notification channel with IDs of changed Carts.
+const changesChannel = "cart_changes"
+
+// listenerPingInterval is how often the LISTEN connection is checked when there are no notifications.
+const listenerPingInterval = 90 * time.Second
+
+// broadcaster notifies subscribers about changes of Carts they watch.
+// Notifications are coalesced, a subscriber that has not received the previous one gets only one.
+type broadcaster struct {
+	mu   sync.Mutex
+	subs map[int64]map[chan struct{}]struct{}
+}
+
+func newBroadcaster() *broadcaster {
+	return &broadcaster{subs: make(map[int64]map[chan struct{}]struct{})}
+}
+
+// subscribe to changes of the Cart. The returned function must be called to unsubscribe.
+func (b *broadcaster) subscribe(cartID int64) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	b.mu.Lock()
+	if b.subs[cartID] == nil {
+		b.subs[cartID] = make(map[chan struct{}]struct{})
+	}
+	b.subs[cartID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(b.subs[cartID], ch)
+		if len(b.subs[cartID]) == 0 {
+			delete(b.subs, cartID)
+		}
+	}
+}
+
+func wake(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// notify subscribers of the Cart.
+func (b *broadcaster) notify(cartID int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs[cartID] {
+		wake(ch)
+	}
+}
+
+// notifyAll subscribers, e.g. when changes could have been missed.
+func (b *broadcaster) notifyAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, subs := range b.subs {
+		for ch := range subs {
+			wake(ch)
+		}
+	}
+}
+
+// Watch returns a channel that receives a value when the Cart may have changed. Only members can watch the Cart.
+// The returned function must be called to stop watching.
+func (c *Carts) Watch(ctx context.Context, cartID int64) (<-chan struct{}, func(), error) {
+	if err := c.authorize(ctx, cartID, RoleViewer); err != nil {
+		return nil, nil, err
+	}
+
+	ch, stop := c.changes.subscribe(cartID)
+	return ch, stop, nil
+}
+
+// ChangeListener delivers changes of Carts made by other instances of the service to local watchers.
+// It uses Postgres LISTEN/NOTIFY, Storage notifies about every Cart change.
+type ChangeListener struct {
+	listener *pq.Listener
+	carts    *Carts
+}
+
+// NewChangeListener connects to the DB and listens for Cart changes.
+func NewChangeListener(dbURL string, c *Carts) (*ChangeListener, error) {
+	l := pq.NewListener(dbURL, 10*time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("Changes listener connection event: %d, error: %s", ev, err)
+		}
+	})
+
+	if err := l.Listen(changesChannel); err != nil {
+		l.Close()
+		return nil, err
+	}
+
+	return &ChangeListener{listener: l, carts: c}, nil
+}
+
+// Run delivers notifications until the context is canceled.
+func (cl *ChangeListener) Run(ctx context.Context) {
+	defer cl.listener.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-cl.listener.Notify:
+			if n == nil {
+				// Connection was reestablished, notifications could have been lost.
+				cl.carts.changes.notifyAll()
+				continue
+			}
+
+			cartID, err := strconv.ParseInt(n.Extra, 10, 64)
+			if err != nil {
+				log.Printf("Failed to parse changed Cart ID: %q, error: %s", n.Extra, err)
+				continue
+			}
+			cl.carts.changes.notify(cartID)
+		case <-time.After(listenerPingInterval):
+			if err := cl.listener.Ping(); err != nil {
+				log.Printf("Changes listener ping failed: %s", err)
+			}
+		}
+	}
+}
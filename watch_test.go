@@ -0,0 +1,109 @@
+package cart
+
+import (
+	"context"
+	"testing"
+)
+
+func received(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestBroadcasterNotify(t *testing.T) {
+	b := newBroadcaster()
+
+	watched, stopWatched := b.subscribe(1)
+	defer stopWatched()
+
+	other, stopOther := b.subscribe(2)
+	defer stopOther()
+
+	b.notify(1)
+	b.notify(1)
+
+	if !received(watched) {
+		t.Error("Expected a notification for the watched Cart")
+	}
+	if received(watched) {
+		t.Error("Expected notifications to be coalesced")
+	}
+	if received(other) {
+		t.Error("Expected no notification for other Cart")
+	}
+
+	b.notifyAll()
+
+	if !received(watched) || !received(other) {
+		t.Error("Expected notifications for all Carts")
+	}
+}
+
+func TestBroadcasterUnsubscribe(t *testing.T) {
+	b := newBroadcaster()
+
+	ch, stop := b.subscribe(1)
+	stop()
+
+	b.notify(1)
+
+	if received(ch) {
+		t.Error("Expected no notification after unsubscribe")
+	}
+
+	if len(b.subs) != 0 {
+		t.Errorf("Got %d Carts with subscribers, expected none", len(b.subs))
+	}
+}
+
+func TestWatchNeedsMember(t *testing.T) {
+	cases := []struct {
+		name          string
+		ctx           context.Context
+		role          Role
+		expectedError error
+	}{
+		{
+			name: "Viewer",
+			ctx:  WithCaller(context.Background(), 2),
+			role: RoleViewer,
+		},
+		{
+			name:          "Not a member",
+			ctx:           WithCaller(context.Background(), 2),
+			expectedError: errPermissionDenied,
+		},
+		{
+			name:          "No caller",
+			ctx:           context.Background(),
+			expectedError: errUnauthenticated,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			storage := &StorageMock{
+				MemberRoleFunc: func(ctx context.Context, cartID, userID int64) (Role, error) {
+					return c.role, nil
+				},
+			}
+			carts := New(storage)
+
+			_, stop, err := carts.Watch(c.ctx, 1)
+			if err != c.expectedError {
+				t.Fatalf("Got error: %v, expected: %v", err, c.expectedError)
+			}
+			if err == nil {
+				stop()
+			}
+
+			if len(carts.changes.subs) != 0 {
+				t.Errorf("Got %d Carts with subscribers, expected none", len(carts.changes.subs))
+			}
+		})
+	}
+}